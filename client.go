@@ -5,18 +5,30 @@
 package agentx
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net"
+	"runtime/debug"
+	"sync/atomic"
 	"time"
 
 	"github.com/Olian04/go-agentx/pdu"
 	"github.com/Olian04/go-agentx/value"
 )
 
+const (
+	// transmitQueueSize defines the number of packets that can be queued for
+	// transmission. All queued packets are written to the connection at once.
+	transmitQueueSize = 64
+
+	// ioBufferSize defines the size of the read and write buffers of the connection.
+	ioBufferSize = 16 << 10
+)
+
 // Client defines an agentx client.
 type Client struct {
 	logger      *slog.Logger
@@ -26,14 +38,21 @@ type Client struct {
 	conn        net.Conn
 	requestChan chan *request
 	sessions    map[uint32]*Session
+	// closed is set by Close, so the receiver doesn't re-connect.
+	closed atomic.Bool
 }
 
 // Dial connects to the provided agentX endpoint.
 func Dial(network, address string, opts ...DialOption) (*Client, error) {
-	options := dialOptions{}
+	options := dialOptions{
+		maxPayloadLength: pdu.DefaultMaxPayloadLength,
+	}
 	for _, dialOption := range opts {
 		dialOption(&options)
 	}
+	if options.maxPayloadLength < pdu.MinMaxPayloadLength {
+		return nil, fmt.Errorf("max payload length %d is below the minimum of %d", options.maxPayloadLength, pdu.MinMaxPayloadLength)
+	}
 
 	conn, err := net.Dial(network, address)
 	if err != nil {
@@ -58,7 +77,7 @@ func Dial(network, address string, opts ...DialOption) (*Client, error) {
 	}
 
 	tx := c.runTransmitter()
-	rx := c.runReceiver()
+	rx := c.runReceiver(tx)
 	c.runDispatcher(tx, rx)
 
 	return c, nil
@@ -66,6 +85,7 @@ func Dial(network, address string, opts ...DialOption) (*Client, error) {
 
 // Close tears down the client.
 func (c *Client) Close() error {
+	c.closed.Store(true)
 	if err := c.conn.Close(); err != nil {
 		return fmt.Errorf("close connection: %w", err)
 	}
@@ -83,81 +103,107 @@ func (c *Client) Session(nameOID value.OID, name string, handler Handler) (*Sess
 }
 
 func (c *Client) runTransmitter() chan *pdu.HeaderPacket {
-	tx := make(chan *pdu.HeaderPacket)
+	tx := make(chan *pdu.HeaderPacket, transmitQueueSize)
 
 	go func() {
 		ctx := context.Background()
+		// The packets are encoded into the buffer of the writer and all queued
+		// packets are written to the connection with a single write. Unlike
+		// net.Buffers, this doesn't keep the encoding of each packet alive until
+		// the write, so the encoder can re-use its buffer.
+		writer := bufio.NewWriterSize(connWriter{client: c}, ioBufferSize)
+		encoder := pdu.NewEncoder(writer)
+		// pending counts the packets in the writer, that are not flushed yet. The
+		// headers are only kept for debug logging.
+		pending := 0
+		var pendingHeaders []pdu.Header
 		for headerPacket := range tx {
-			headerPacketBytes, err := headerPacket.MarshalBinary()
-			if err != nil {
-				// recycle on error too
-				if headerPacket.Header != nil {
-					releaseHeader(headerPacket.Header)
-				}
-				releaseHeaderPacket(headerPacket)
-				c.logger.Error("packet marshal error",
+			if err := c.encode(encoder, headerPacket); err != nil {
+				c.logger.Error("packet write error",
 					getPacketHeaderSlogAttrs(headerPacket.Header),
 					slog.Any("err", err),
 				)
-				continue
-			}
-			// Write all bytes to the connection (handle partial writes)
-			left := headerPacketBytes
-			for len(left) > 0 {
-				n, err := c.conn.Write(left)
-				if err != nil {
-					if headerPacket.Header != nil {
-						releaseHeader(headerPacket.Header)
+				if response, ok := headerPacket.Packet.(*pdu.Response); ok {
+					// answer with an error, so the master agent doesn't wait for a response
+					headerPacket.Packet = &pdu.Response{
+						UpTime: response.UpTime,
+						Error:  pdu.ErrorProcessing,
+						Index:  failingVariable(response.Variables),
+					}
+					if err := encoder.Encode(headerPacket); err != nil {
+						c.logger.Error("packet write error",
+							getPacketHeaderSlogAttrs(headerPacket.Header),
+							slog.Any("err", err),
+						)
+					} else {
+						pending++
 					}
-					releaseHeaderPacket(headerPacket)
-					c.logger.Error("packet write error",
-						getPacketHeaderSlogAttrs(headerPacket.Header),
-						slog.Any("err", err),
-					)
-					break
 				}
-				left = left[n:]
-			}
-			if len(left) > 0 {
-				// write failed; skip logging "sent"
-				if headerPacket.Header != nil {
-					releaseHeader(headerPacket.Header)
+			} else {
+				pending++
+				if c.logger.Enabled(ctx, slog.LevelDebug) {
+					pendingHeaders = append(pendingHeaders, *headerPacket.Header)
 				}
+			}
+			// recycle header and headerPacket of responses. Requests are kept by
+			// the session in order to re-open it after a re-connect.
+			if headerPacket.Packet.Type() == pdu.TypeResponse {
+				releaseHeader(headerPacket.Header)
 				releaseHeaderPacket(headerPacket)
-				c.logger.Error("packet write error",
-					getPacketHeaderSlogAttrs(headerPacket.Header),
-					slog.String("err", "short write"),
-				)
-				continue
 			}
-			if c.logger.Enabled(ctx, slog.LevelDebug) {
-				c.logger.Debug("packet sent", getPacketHeaderSlogAttrs(headerPacket.Header))
+
+			// Coalesce all queued packets into a single write to the connection.
+			if len(tx) > 0 {
+				continue
 			}
-			// recycle header and headerPacket after successful send
-			if headerPacket.Header != nil {
-				releaseHeader(headerPacket.Header)
+			if err := writer.Flush(); err != nil {
+				c.logger.Error("packet write error",
+					slog.Int("dropped_packets", pending),
+					slog.Any("err", err),
+				)
+				// The master agent closes the sessions of a broken connection, so
+				// the buffered responses can't be sent on another one. Closing the
+				// connection lets the receiver re-connect and re-open the sessions.
+				_ = c.conn.Close()
+				writer.Reset(connWriter{client: c})
+			} else {
+				for i := range pendingHeaders {
+					c.logger.Debug("packet sent", getPacketHeaderSlogAttrs(&pendingHeaders[i]))
+				}
 			}
-			releaseHeaderPacket(headerPacket)
+			pending = 0
+			pendingHeaders = pendingHeaders[:0]
 		}
 	}()
 
 	return tx
 }
 
-func (c *Client) runReceiver() chan *pdu.HeaderPacket {
+func (c *Client) runReceiver(tx chan *pdu.HeaderPacket) chan *pdu.HeaderPacket {
 	rx := make(chan *pdu.HeaderPacket)
 
 	go func() {
 		ctx := context.Background()
+		decoder := c.newDecoder()
 	mainLoop:
 		for {
-			headerBytes := acquireHeaderBuf()
-			if _, err := io.ReadFull(c.conn, headerBytes[:]); err != nil {
-				releaseHeaderBuf(headerBytes)
-				if errors.Is(err, net.ErrClosed) {
+			headerPacket, err := decoder.Decode()
+			if err != nil {
+				if errors.Is(err, net.ErrClosed) && c.closed.Load() {
 					return
 				}
-				if err == io.EOF {
+				if decodeErr := (*pdu.DecodeError)(nil); errors.As(err, &decodeErr) {
+					c.logger.Error("unable to decode packet",
+						getPacketHeaderSlogAttrs(decodeErr.Header),
+						slog.Any("err", err),
+					)
+					if decodeErr.Header.Type != pdu.TypeResponse && !errors.Is(err, pdu.ErrUnsupportedType) {
+						tx <- parseErrorResponse(decodeErr.Header)
+					}
+					continue mainLoop
+				}
+				// the connection is also closed by the transmitter after a write error
+				if err == io.EOF || err == io.ErrUnexpectedEOF || errors.Is(err, net.ErrClosed) {
 					c.logger.Info("lost connection", slog.Duration("re-connect-in", c.options.reconnectInterval))
 				reopenLoop:
 					for {
@@ -172,6 +218,7 @@ func (c *Client) runReceiver() chan *pdu.HeaderPacket {
 							_ = tcp.SetKeepAlive(true)
 						}
 						c.conn = conn
+						decoder = c.newDecoder()
 						go func() {
 							for _, session := range c.sessions {
 								delete(c.sessions, session.ID())
@@ -193,55 +240,16 @@ func (c *Client) runReceiver() chan *pdu.HeaderPacket {
 				continue mainLoop
 			}
 
-			header := &pdu.Header{}
-			if err := header.UnmarshalBinary(headerBytes[:]); err != nil {
-				releaseHeaderBuf(headerBytes)
-				c.logger.Error("header unmarshal error",
-					getPacketHeaderSlogAttrs(header),
-					slog.Any("err", err),
-				)
-				continue mainLoop
-			}
-
 			if c.logger.Enabled(ctx, slog.LevelDebug) {
-				c.logger.Debug("packet received", getPacketHeaderSlogAttrs(header))
-			}
-			releaseHeaderBuf(headerBytes)
-
-			var packet pdu.Packet
-			switch header.Type {
-			case pdu.TypeResponse:
-				packet = &pdu.Response{}
-			case pdu.TypeGet:
-				packet = &pdu.Get{}
-			case pdu.TypeGetNext:
-				packet = &pdu.GetNext{}
-			default:
-				c.logger.Error("unable to handle packet", getPacketHeaderSlogAttrs(header))
-				continue mainLoop
-			}
-
-			packetHandle, packetBytes := acquireIOBuf(int(header.PayloadLength))
-			if _, err := io.ReadFull(c.conn, packetBytes); err != nil {
-				releaseIOBuf(packetHandle)
-				c.logger.Error("unable to read packet",
-					getPacketHeaderSlogAttrs(header),
-					slog.Any("err", err),
-				)
-				continue mainLoop
+				c.logger.Debug("packet received", getPacketHeaderSlogAttrs(headerPacket.Header))
 			}
 
-			if err := packet.UnmarshalBinary(packetBytes); err != nil {
-				releaseIOBuf(packetHandle)
-				c.logger.Error("unable to unmarshal packet",
-					getPacketHeaderSlogAttrs(header),
-					slog.Any("err", err),
-				)
-				continue mainLoop
+			switch headerPacket.Header.Type {
+			case pdu.TypeResponse, pdu.TypeGet, pdu.TypeGetNext:
+				rx <- headerPacket
+			default:
+				c.logger.Error("unable to handle packet", getPacketHeaderSlogAttrs(headerPacket.Header))
 			}
-
-			releaseIOBuf(packetHandle)
-			rx <- &pdu.HeaderPacket{Header: header, Packet: packet}
 		}
 	}()
 
@@ -278,6 +286,68 @@ func (c *Client) runDispatcher(tx, rx chan *pdu.HeaderPacket) {
 	}()
 }
 
+// encode encodes the packet. A panic, e.g. of a value with a faulty
+// implementation, is reported and returned as error.
+func (c *Client) encode(encoder *pdu.Encoder, hp *pdu.HeaderPacket) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.reportPanic(hp.Header, r)
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return encoder.Encode(hp)
+}
+
+// reportPanic logs the recovered panic, that occurred while the packet with the
+// provided header was processed, and passes it to the panic handler.
+func (c *Client) reportPanic(header *pdu.Header, recovered any) {
+	stack := debug.Stack()
+	c.logger.Error("recovered panic",
+		getPacketHeaderSlogAttrs(header),
+		slog.Any("panic", recovered),
+		slog.String("stack", string(stack)),
+	)
+	if c.options.panicHandler != nil {
+		c.options.panicHandler(recovered, stack)
+	}
+}
+
+// failingVariable returns the 1-based index of the first variable, that can't
+// be encoded, or 0 if all variables can be encoded.
+func failingVariable(variables pdu.Variables) uint16 {
+	encodes := func(v *pdu.Variable) (ok bool) {
+		defer func() {
+			if recover() != nil {
+				ok = false
+			}
+		}()
+		_, err := v.MarshalBinary()
+		return err == nil
+	}
+	for i := range variables {
+		if !encodes(&variables[i]) {
+			return uint16(i + 1)
+		}
+	}
+	return 0
+}
+
+func (c *Client) newDecoder() *pdu.Decoder {
+	decoder := pdu.NewDecoder(bufio.NewReaderSize(c.conn, ioBufferSize))
+	decoder.SetMaxPayloadLength(c.options.maxPayloadLength)
+	return decoder
+}
+
+// connWriter writes to the current connection of the client, which
+// changes on every re-connect.
+type connWriter struct {
+	client *Client
+}
+
+func (w connWriter) Write(p []byte) (int, error) {
+	return w.client.conn.Write(p)
+}
+
 func (c *Client) request(hp *pdu.HeaderPacket) *pdu.HeaderPacket {
 	req := acquireRequest()
 	req.headerPacket = hp
@@ -291,6 +361,20 @@ func (c *Client) request(hp *pdu.HeaderPacket) *pdu.HeaderPacket {
 	return headerPacket
 }
 
+// parseErrorResponse returns a response to the request with the provided header,
+// that reports a parse error to the master agent (RFC 2741, section 7.2.2).
+func parseErrorResponse(header *pdu.Header) *pdu.HeaderPacket {
+	responseHeader := acquireHeader()
+	responseHeader.SessionID = header.SessionID
+	responseHeader.TransactionID = header.TransactionID
+	responseHeader.PacketID = header.PacketID
+
+	hp := acquireHeaderPacket()
+	hp.Header = responseHeader
+	hp.Packet = &pdu.Response{Error: pdu.ErrorParse}
+	return hp
+}
+
 func getPacketHeaderSlogAttrs(header *pdu.Header) slog.Attr {
 	return slog.GroupAttrs("packet_header",
 		slog.String("packet_type", header.Type.String()),
@@ -0,0 +1,255 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package agentx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Olian04/go-agentx/pdu"
+	"github.com/Olian04/go-agentx/value"
+)
+
+// countingConn counts the writes to the connection. Writes block until release
+// is closed, if it is set.
+type countingConn struct {
+	net.Conn
+	started chan struct{}
+	release chan struct{}
+	err     error
+
+	once   sync.Once
+	mu     sync.Mutex
+	writes int
+	bytes  int
+	closed bool
+}
+
+func newCountingConn() *countingConn {
+	return &countingConn{started: make(chan struct{})}
+}
+
+func (c *countingConn) Write(p []byte) (int, error) {
+	c.once.Do(func() { close(c.started) })
+	if c.release != nil {
+		<-c.release
+	}
+	if c.err != nil {
+		return 0, c.err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writes++
+	c.bytes += len(p)
+	return len(p), nil
+}
+
+func (c *countingConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return nil
+}
+
+func (c *countingConn) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+func (c *countingConn) counts() (writes, bytes int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.writes, c.bytes
+}
+
+// testResponse returns a response packet and its encoded size.
+func testResponse(packetID uint32) (*pdu.HeaderPacket, int) {
+	hp := acquireHeaderPacket()
+	hp.Header = acquireHeader()
+	hp.Header.SessionID = 1
+	hp.Header.PacketID = packetID
+	response := &pdu.Response{}
+	response.Variables.Add(value.MustParseOID("1.3.6.1.4.1.45995.3.1"), pdu.VariableTypeOctetString, value.OctetString("test"))
+	hp.Packet = response
+	return hp, hp.ByteSize()
+}
+
+// recordHandler records the messages of the log records.
+type recordHandler struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (h *recordHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *recordHandler) WithAttrs([]slog.Attr) slog.Handler       { return h }
+func (h *recordHandler) WithGroup(string) slog.Handler            { return h }
+
+func (h *recordHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.messages = append(h.messages, r.Message)
+	return nil
+}
+
+func (h *recordHandler) Messages() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.messages...)
+}
+
+func TestTransmitterCoalescesWrites(t *testing.T) {
+	conn := newCountingConn()
+	conn.release = make(chan struct{})
+	c := &Client{logger: slog.New(slog.DiscardHandler), conn: conn}
+	tx := c.runTransmitter()
+	defer close(tx)
+
+	hp, size := testResponse(0)
+	tx <- hp
+	// the first packet is written on its own, the following ones queue up
+	<-conn.started
+	for i := 1; i <= 10; i++ {
+		hp, _ := testResponse(uint32(i))
+		tx <- hp
+	}
+	close(conn.release)
+
+	require.Eventually(t, func() bool {
+		_, bytes := conn.counts()
+		return bytes == 11*size
+	}, time.Second, time.Millisecond)
+	writes, _ := conn.counts()
+	assert.Equal(t, 2, writes)
+}
+
+func TestTransmitterLogging(t *testing.T) {
+	t.Run("Sent", func(t *testing.T) {
+		conn := newCountingConn()
+		handler := &recordHandler{}
+		c := &Client{logger: slog.New(handler), conn: conn}
+		tx := c.runTransmitter()
+		defer close(tx)
+
+		hp, size := testResponse(0)
+		tx <- hp
+		require.Eventually(t, func() bool {
+			_, bytes := conn.counts()
+			return bytes == size && len(handler.Messages()) == 1
+		}, time.Second, time.Millisecond)
+		assert.Equal(t, []string{"packet sent"}, handler.Messages())
+	})
+
+	t.Run("Flush error", func(t *testing.T) {
+		conn := newCountingConn()
+		conn.err = errors.New("broken connection")
+		handler := &recordHandler{}
+		c := &Client{logger: slog.New(handler), conn: conn}
+		tx := c.runTransmitter()
+		defer close(tx)
+
+		hp, _ := testResponse(0)
+		tx <- hp
+		require.Eventually(t, func() bool {
+			return len(handler.Messages()) == 1
+		}, time.Second, time.Millisecond)
+		assert.Equal(t, []string{"packet write error"}, handler.Messages())
+		// the connection is closed, so the receiver re-connects
+		assert.True(t, conn.isClosed())
+	})
+}
+
+// failingWriteConn is a connection, whose writes fail.
+type failingWriteConn struct {
+	net.Conn
+}
+
+func (c failingWriteConn) Write([]byte) (int, error) {
+	return 0, errors.New("broken connection")
+}
+
+func TestClientReconnectAfterWriteError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	accepted := make(chan net.Conn, 2)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- conn
+		}
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer (<-accepted).Close()
+
+	handler := &recordHandler{}
+	c := &Client{
+		logger:   slog.New(handler),
+		network:  "tcp",
+		address:  listener.Addr().String(),
+		options:  dialOptions{maxPayloadLength: pdu.DefaultMaxPayloadLength, reconnectInterval: time.Millisecond},
+		conn:     failingWriteConn{Conn: conn},
+		sessions: make(map[uint32]*Session),
+	}
+	tx := c.runTransmitter()
+	defer close(tx)
+	c.runReceiver(tx)
+
+	hp, _ := testResponse(0)
+	tx <- hp
+	select {
+	case conn := <-accepted:
+		defer conn.Close()
+	case <-time.After(time.Second):
+		t.Fatal("no re-connect after the write error")
+	}
+	require.Eventually(t, func() bool {
+		return slices.Contains(handler.Messages(), "re-connect successful")
+	}, time.Second, time.Millisecond)
+	require.NoError(t, c.Close())
+}
+
+// BenchmarkTransmitterParallel reports the number of writes per packet, that
+// parallel handlers send.
+func BenchmarkTransmitterParallel(b *testing.B) {
+	conn := newCountingConn()
+	c := &Client{logger: slog.New(slog.DiscardHandler), conn: conn}
+	tx := c.runTransmitter()
+	defer close(tx)
+	_, size := testResponse(0)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			hp, _ := testResponse(0)
+			tx <- hp
+		}
+	})
+	for {
+		if _, bytes := conn.counts(); bytes == b.N*size {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	b.StopTimer()
+
+	writes, _ := conn.counts()
+	b.ReportMetric(float64(writes)/float64(b.N), "writes/packet")
+}
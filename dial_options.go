@@ -9,6 +9,8 @@ type dialOptions struct {
 	logger            *slog.Logger
 	timeout           time.Duration
 	reconnectInterval time.Duration
+	maxPayloadLength  uint32
+	panicHandler      func(recovered any, stack []byte)
 }
 
 type DialOption func(o *dialOptions)
@@ -30,3 +32,24 @@ func WithReconnectInterval(value time.Duration) DialOption {
 		o.reconnectInterval = value
 	}
 }
+
+// WithMaxPayloadLength sets the maximum payload length of the packets that are
+// exchanged with the master agent. Received packets that exceed the limit are
+// rejected with a parse error and responses that would exceed it are replaced by
+// truncated or replaced by a tooBig error. Defaults to pdu.DefaultMaxPayloadLength,
+// values below pdu.MinMaxPayloadLength are rejected by Dial.
+func WithMaxPayloadLength(value uint32) DialOption {
+	return func(o *dialOptions) {
+		o.maxPayloadLength = value
+	}
+}
+
+// WithPanicHandler sets a function, that is called with the recovered value and
+// the stack trace, whenever a panic is recovered while a request is handled or a
+// packet is encoded, e.g. to report it to an error tracker. The panic is logged
+// and answered with a processing error in any case.
+func WithPanicHandler(value func(recovered any, stack []byte)) DialOption {
+	return func(o *dialOptions) {
+		o.panicHandler = value
+	}
+}
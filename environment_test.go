@@ -20,7 +20,7 @@ type environment struct {
 	client *agentx.Client
 }
 
-func setUpTestEnvironment(tb testing.TB) *environment {
+func setUpTestEnvironment(tb testing.TB, opts ...agentx.DialOption) *environment {
 	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelError,
 	})).With("test", tb.Name()))
@@ -34,11 +34,11 @@ func setUpTestEnvironment(tb testing.TB) *environment {
 	require.NoError(tb, cmd.Start())
 	time.Sleep(500 * time.Millisecond)
 
-	client, err := agentx.Dial("tcp", "127.0.0.1:30705",
+	client, err := agentx.Dial("tcp", "127.0.0.1:30705", append([]agentx.DialOption{
 		agentx.WithLogger(slog.Default()),
-		agentx.WithTimeout(60*time.Second),
-		agentx.WithReconnectInterval(1*time.Second),
-	)
+		agentx.WithTimeout(60 * time.Second),
+		agentx.WithReconnectInterval(1 * time.Second),
+	}, opts...)...)
 	require.NoError(tb, err)
 
 	tb.Cleanup(func() {
@@ -0,0 +1,90 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package agentx
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Olian04/go-agentx/pdu"
+	"github.com/Olian04/go-agentx/value"
+)
+
+// Getter returns the current value of an object. The value is converted like
+// the values returned by a Handler. A nil value without error marks the object
+// as currently absent, which is answered with noSuchInstance.
+type Getter func(context.Context) (any, error)
+
+// FuncHandler is a helper that binds oids to getter functions, which are called
+// on each request, so the values are always computed from the current state.
+// All methods are safe for concurrent use.
+type FuncHandler struct {
+	mu    sync.RWMutex
+	items value.Tree[funcItem]
+}
+
+type funcItem struct {
+	t   pdu.VariableType
+	get Getter
+}
+
+// Add binds the provided getter to the oid. The type can be zero, if the getter
+// returns value.Value values. An existing binding for the oid is replaced.
+func (f *FuncHandler) Add(oid string, t pdu.VariableType, get Getter) {
+	parsedOID := value.MustParseOID(oid)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.items.Set(parsedOID, funcItem{t: t, get: get})
+}
+
+// Remove removes the binding of the provided oid. It returns true, if the
+// binding existed.
+func (f *FuncHandler) Remove(oid value.OID) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.items.Delete(oid)
+}
+
+// Get calls the getter, that is bound to the provided oid, and returns its value.
+func (f *FuncHandler) Get(ctx context.Context, oid value.OID) (value.OID, pdu.VariableType, any, error) {
+	f.mu.RLock()
+	item, ok := f.items.Get(oid)
+	f.mu.RUnlock()
+
+	if !ok {
+		return nil, pdu.VariableTypeNoSuchObject, nil, nil
+	}
+	v, err := item.get(ctx)
+	if err != nil {
+		return oid, pdu.VariableTypeNull, nil, err
+	}
+	if v == nil {
+		return nil, pdu.VariableTypeNoSuchInstance, nil, nil
+	}
+	return oid, item.t, v, nil
+}
+
+// GetNext calls the getter of the oid, that follows the provided one, and
+// returns its value. Absent objects are skipped.
+func (f *FuncHandler) GetNext(ctx context.Context, from value.OID, includeFrom bool, to value.OID) (value.OID, pdu.VariableType, any, error) {
+	for {
+		f.mu.RLock()
+		oid, item, ok := f.items.Successor(from, includeFrom)
+		f.mu.RUnlock()
+
+		if !ok || value.CompareOIDs(oid, to) != -1 {
+			return nil, pdu.VariableTypeNoSuchObject, nil, nil
+		}
+		v, err := item.get(ctx)
+		if err != nil {
+			return oid, pdu.VariableTypeNull, nil, err
+		}
+		if v != nil {
+			return oid, item.t, v, nil
+		}
+		from, includeFrom = oid, false
+	}
+}
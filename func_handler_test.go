@@ -0,0 +1,83 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package agentx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Olian04/go-agentx"
+	"github.com/Olian04/go-agentx/pdu"
+	"github.com/Olian04/go-agentx/value"
+)
+
+func TestFuncHandler(t *testing.T) {
+	ctx := context.Background()
+	end := value.MustParseOID("1.3.6.1.4.1.45996")
+	queueLength := 3
+	var absent bool
+
+	fh := &agentx.FuncHandler{}
+	fh.Add("1.3.6.1.4.1.45995.3.1", pdu.VariableTypeGauge32, func(context.Context) (any, error) {
+		return queueLength, nil
+	})
+	fh.Add("1.3.6.1.4.1.45995.3.2", 0, func(context.Context) (any, error) {
+		if absent {
+			return nil, nil
+		}
+		return value.OctetString("present"), nil
+	})
+	fh.Add("1.3.6.1.4.1.45995.3.3", pdu.VariableTypeInteger, func(context.Context) (any, error) {
+		return queueLength * 2, nil
+	})
+
+	oid, typ, v, err := fh.Get(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.1"))
+	require.NoError(t, err)
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.3.1"), oid)
+	assert.Equal(t, pdu.VariableTypeGauge32, typ)
+	assert.Equal(t, 3, v)
+
+	queueLength = 4
+	_, _, v, _ = fh.Get(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.1"))
+	assert.Equal(t, 4, v)
+
+	oid, _, v, err = fh.GetNext(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.1"), false, end)
+	require.NoError(t, err)
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.3.2"), oid)
+	assert.Equal(t, value.OctetString("present"), v)
+
+	absent = true
+	oid, typ, _, _ = fh.Get(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.2"))
+	assert.Nil(t, oid)
+	assert.Equal(t, pdu.VariableTypeNoSuchInstance, typ)
+	oid, _, v, _ = fh.GetNext(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.1"), false, end)
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.3.3"), oid)
+	assert.Equal(t, 8, v)
+
+	assert.True(t, fh.Remove(value.MustParseOID("1.3.6.1.4.1.45995.3.3")))
+	oid, _, _, _ = fh.GetNext(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.1"), false, end)
+	assert.Nil(t, oid)
+}
+
+func TestFuncHandlerError(t *testing.T) {
+	ctx := context.Background()
+	errFailed := errors.New("failed")
+	fh := &agentx.FuncHandler{}
+	fh.Add("1.3.6.1.4.1.45995.3.1", pdu.VariableTypeGauge32, func(context.Context) (any, error) {
+		return nil, errFailed
+	})
+
+	oid, typ, _, err := fh.Get(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.1"))
+	assert.ErrorIs(t, err, errFailed)
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.3.1"), oid)
+	assert.Equal(t, pdu.VariableTypeNull, typ)
+
+	_, _, _, err = fh.GetNext(ctx, value.MustParseOID("1.3.6.1.4.1.45995"), false, value.MustParseOID("1.3.6.1.4.1.45996"))
+	assert.ErrorIs(t, err, errFailed)
+}
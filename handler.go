@@ -13,6 +13,22 @@ import (
 
 // Handler defines an interface for a handler of events that
 // might occure during a session.
+//
+// The returned values are either a value.Value, in which case the returned
+// type can be left zero, or any Go value that can be converted to the returned
+// type by value.Convert. Values that can't be converted are answered with genErr.
+//
+// Get returns a nil oid, if the requested oid doesn't exist. The returned type
+// is then either pdu.VariableTypeNoSuchInstance, if the object exists but the
+// instance doesn't (e.g. a missing row of a table column), or any other type,
+// which is answered with noSuchObject. GetNext returns a nil oid at the end of
+// the view.
+//
+// A returned error fails the request. The response reports the pdu.Error in the
+// error chain (e.g. fmt.Errorf("...: %w", pdu.ErrorNoAccess)) or processingError,
+// if there is none, together with the position of the failing variable. Panics
+// are recovered by the session for each variable and answered the same way with
+// processingError.
 type Handler interface {
 	Get(context.Context, value.OID) (value.OID, pdu.VariableType, any, error)
 	GetNext(context.Context, value.OID, bool, value.OID) (value.OID, pdu.VariableType, any, error)
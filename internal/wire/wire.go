@@ -0,0 +1,70 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+// Package wire implements the encoding primitives of the AgentX protocol
+// (RFC 2741, section 5), that are shared by the value and pdu packages.
+package wire
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// MaxEncodedSubidentifiers defines the maximum number of subidentifiers, that
+// fit into the length field of an encoded object identifier.
+const MaxEncodedSubidentifiers = 0xff
+
+var zeroPadding [3]byte
+
+// Padding returns the number of padding bytes, that follow l octets.
+func Padding(l int) int {
+	return (4 - (l % 4)) & 3
+}
+
+// AppendPadding appends the padding bytes, that follow l octets, to b.
+func AppendPadding(b []byte, l int) []byte {
+	return append(b, zeroPadding[:Padding(l)]...)
+}
+
+// OctetsSize returns the encoded size of l length-prefixed and padded octets.
+func OctetsSize(l int) int {
+	return 4 + l + Padding(l)
+}
+
+// AppendOctets appends the length-prefixed and padded octets to b.
+func AppendOctets[T ~string | ~[]byte](b []byte, octets T) []byte {
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(octets)))
+	b = append(b, octets...)
+	return AppendPadding(b, len(octets))
+}
+
+// CompressOID splits the oid into the prefix and the remaining subidentifiers.
+// Oids of the form 1.3.6.1.<prefix>... with a prefix from 1 to 255 are
+// compressed, all others are returned with prefix 0, which means no prefix.
+func CompressOID(oid []uint32) (uint8, []uint32) {
+	if len(oid) > 4 && oid[0] == 1 && oid[1] == 3 && oid[2] == 6 && oid[3] == 1 && oid[4] >= 1 && oid[4] <= 0xff {
+		return uint8(oid[4]), oid[5:]
+	}
+	return 0, oid
+}
+
+// OIDSize returns the encoded size of an object identifier with the provided
+// number of (compressed) subidentifiers.
+func OIDSize(count int) int {
+	return 4 + count*4
+}
+
+// AppendOID appends the encoded object identifier with the provided prefix,
+// include field and subidentifiers to b.
+func AppendOID(b []byte, prefix uint8, include byte, subids []uint32) ([]byte, error) {
+	if len(subids) > MaxEncodedSubidentifiers {
+		return nil, fmt.Errorf("object identifier has %d subidentifiers, at most %d can be encoded", len(subids), MaxEncodedSubidentifiers)
+	}
+	// the fourth byte is reserved (0x00)
+	b = append(b, byte(len(subids)), prefix, include, 0x00)
+	for _, sub := range subids {
+		b = binary.LittleEndian.AppendUint32(b, sub)
+	}
+	return b, nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package wire_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Olian04/go-agentx/internal/wire"
+)
+
+func TestOctets(t *testing.T) {
+	for l, size := range map[int]int{0: 4, 1: 8, 3: 8, 4: 8, 5: 12} {
+		b := wire.AppendOctets(nil, make([]byte, l))
+		assert.Len(t, b, size, l)
+		assert.Equal(t, size, wire.OctetsSize(l), l)
+	}
+	assert.Equal(t, []byte{2, 0, 0, 0, 'a', 'b', 0, 0}, wire.AppendOctets(nil, "ab"))
+}
+
+func TestCompressOID(t *testing.T) {
+	tests := []struct {
+		oid    []uint32
+		prefix uint8
+		subids []uint32
+	}{
+		{[]uint32{1, 3, 6, 1, 4, 1, 45995}, 4, []uint32{1, 45995}},
+		{[]uint32{1, 3, 6, 1, 255}, 255, []uint32{}},
+		// prefix 0 means no prefix on the wire
+		{[]uint32{1, 3, 6, 1, 0, 5}, 0, []uint32{1, 3, 6, 1, 0, 5}},
+		{[]uint32{1, 3, 6, 1, 256}, 0, []uint32{1, 3, 6, 1, 256}},
+		{[]uint32{1, 3, 6, 1}, 0, []uint32{1, 3, 6, 1}},
+		{[]uint32{1, 3, 6, 2, 4}, 0, []uint32{1, 3, 6, 2, 4}},
+	}
+	for _, test := range tests {
+		prefix, subids := wire.CompressOID(test.oid)
+		assert.Equal(t, test.prefix, prefix, test.oid)
+		assert.Equal(t, test.subids, subids, test.oid)
+	}
+}
+
+func TestAppendOID(t *testing.T) {
+	b, err := wire.AppendOID(nil, 4, 1, []uint32{1, 2})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{2, 4, 1, 0, 1, 0, 0, 0, 2, 0, 0, 0}, b)
+	assert.Len(t, b, wire.OIDSize(2))
+
+	_, err = wire.AppendOID(nil, 0, 0, make([]uint32, 256))
+	assert.Error(t, err)
+}
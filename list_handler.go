@@ -6,6 +6,9 @@ package agentx
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/Olian04/go-agentx/pdu"
 	"github.com/Olian04/go-agentx/value"
@@ -13,64 +16,218 @@ import (
 
 // ListHandler is a helper that takes a list of oids and implements
 // a default behaviour for that list.
+//
+// All methods are safe for concurrent use. Items are never modified in place
+// by Set or Replace, so readers always see complete items.
 type ListHandler struct {
-	oids  []value.OID
-	items map[string]*ListItem
-	// itemsByIndex mirrors oids by index for fast lookup without string conversions
-	itemsByIndex []*ListItem
+	// SnapshotTimeout enables the snapshot mode, if set. In snapshot mode,
+	// requests are served from a copy-on-write view of the items, which is
+	// captured by the first request of a session. The snapshot expires, once the
+	// session has been idle for the timeout, so a walk, whose requests follow each
+	// other within the timeout, sees consistent tables. The last transaction of
+	// an expired snapshot is still served from it, until a request of another
+	// transaction replaces the snapshot.
+	//
+	// All requests of a session share the snapshot, as the master agent doesn't
+	// tell, which manager or walk a request belongs to. Updates become visible
+	// with the next snapshot, so the timeout must be shorter than the pause
+	// between the walks of the managers.
+	SnapshotTimeout time.Duration
+
+	mu    sync.RWMutex
+	items *value.Tree[*ListItem]
+	// shared is set, if items is referenced by a snapshot and must be cloned
+	// before it is modified.
+	shared    bool
+	snapshots map[uint32]*listSnapshot
+	// objects contains the declared objects (see AddObject).
+	objects value.Tree[struct{}]
 }
 
-// Add adds a list item for the provided oid and returns it.
-func (l *ListHandler) Add(oid string) *ListItem {
-	if l.items == nil {
-		l.items = make(map[string]*ListItem)
+type listSnapshot struct {
+	items *value.Tree[*ListItem]
+	// expires (in unix nanoseconds) and transactionID are updated by the
+	// requests, that are served from the snapshot.
+	expires       atomic.Int64
+	transactionID atomic.Uint32
+}
+
+// use returns true, if the request of the provided transaction is served from
+// the snapshot, and pushes its expiry forward. An expired snapshot only serves
+// the transaction, that used it last.
+func (s *listSnapshot) use(transactionID uint32, pinned bool, now time.Time, timeout time.Duration) bool {
+	if now.UnixNano() >= s.expires.Load() {
+		return pinned && s.transactionID.Load() == transactionID
 	}
+	s.transactionID.Store(transactionID)
+	s.expires.Store(now.Add(timeout).UnixNano())
+	return true
+}
 
-	parsedOID := value.MustParseOID(oid)
+// Add adds a list item for the provided oid and returns it. An existing item
+// for the oid is replaced. The returned item must not be modified while the
+// handler is serving requests, use Set instead.
+func (l *ListHandler) Add(oid string) *ListItem {
 	item := &ListItem{}
-	// Insert into sorted oids and parallel itemsByIndex slice
-	idx := value.LowerBound(l.oids, parsedOID, true)
-	if idx == len(l.oids) {
-		l.oids = append(l.oids, parsedOID)
-		l.itemsByIndex = append(l.itemsByIndex, item)
-	} else {
-		l.oids = append(l.oids, nil)
-		copy(l.oids[idx+1:], l.oids[idx:])
-		l.oids[idx] = parsedOID
-		l.itemsByIndex = append(l.itemsByIndex, nil)
-		copy(l.itemsByIndex[idx+1:], l.itemsByIndex[idx:])
-		l.itemsByIndex[idx] = item
-	}
-	l.items[oid] = item
+	parsedOID := value.MustParseOID(oid)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tree().Set(parsedOID, item)
 	return item
 }
 
+// AddObject declares the object (e.g. a table column) with the provided oid.
+// Get requests for missing instances below a declared object are answered with
+// noSuchInstance instead of noSuchObject. Declared objects are kept by Replace.
+func (l *ListHandler) AddObject(oid string) {
+	parsedOID := value.MustParseOID(oid)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.objects.Set(parsedOID, struct{}{})
+}
+
+// Set sets the type and the value of the item for the provided oid. The item
+// is created, if it doesn't exist. If the type is zero and the value is a
+// value.Value, the type is taken from the value.
+func (l *ListHandler) Set(oid value.OID, t pdu.VariableType, v any) {
+	if typed, ok := v.(value.Value); ok && t == 0 {
+		t = pdu.VariableType(typed.Type())
+	}
+	item := &ListItem{Type: t, Value: v}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tree().Set(oid, item)
+}
+
+// Remove removes the item for the provided oid. It returns true, if the item
+// existed.
+func (l *ListHandler) Remove(oid value.OID) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.tree().Delete(oid)
+}
+
+// Replace replaces all items of the handler with the provided ones, which are
+// keyed by their oid in dotted notation. Requests see either the previous or
+// the new items, but never a mix of them.
+func (l *ListHandler) Replace(items map[string]ListItem) error {
+	tree := &value.Tree[*ListItem]{}
+	for oid, item := range items {
+		parsedOID, err := value.ParseOID(oid)
+		if err != nil {
+			return err
+		}
+		tree.Set(parsedOID, &item)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.items = tree
+	l.shared = false
+	return nil
+}
+
 // Get tries to find the provided oid and returns the corresponding value.
 func (l *ListHandler) Get(ctx context.Context, oid value.OID) (value.OID, pdu.VariableType, any, error) {
-	if l.items == nil {
-		return nil, pdu.VariableTypeNoSuchObject, nil, nil
+	items, unlock := l.view(ctx)
+	var (
+		item *ListItem
+		ok   bool
+	)
+	if items != nil {
+		item, ok = items.Get(oid)
 	}
+	unlock()
 
-	// Binary search to avoid oid.String allocations and map lookup
-	idx := value.LowerBound(l.oids, oid, true)
-	if idx < len(l.oids) && value.CompareOIDs(l.oids[idx], oid) == 0 {
-		item := l.itemsByIndex[idx]
+	if ok {
 		return oid, item.Type, item.Value, nil
 	}
+	if l.isInstanceOfObject(oid) {
+		return nil, pdu.VariableTypeNoSuchInstance, nil, nil
+	}
 	return nil, pdu.VariableTypeNoSuchObject, nil, nil
 }
 
+// isInstanceOfObject returns true, if the oid is located below a declared object.
+func (l *ListHandler) isInstanceOfObject(oid value.OID) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for parent := oid.Parent(); len(parent) > 0; parent = parent.Parent() {
+		if _, ok := l.objects.Get(parent); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // GetNext tries to find the value that follows the provided oid and returns it.
 func (l *ListHandler) GetNext(ctx context.Context, from value.OID, includeFrom bool, to value.OID) (value.OID, pdu.VariableType, any, error) {
-	if l.items == nil {
+	items, unlock := l.view(ctx)
+	defer unlock()
+
+	if items == nil {
 		return nil, pdu.VariableTypeNoSuchObject, nil, nil
 	}
+	oid, item, ok := items.Successor(from, includeFrom)
+	if ok && value.CompareOIDs(oid, to) == -1 {
+		return oid, item.Type, item.Value, nil
+	}
+	return nil, pdu.VariableTypeNoSuchObject, nil, nil
+}
 
-	idx := value.LowerBound(l.oids, from, includeFrom)
-	if idx < len(l.oids) && value.CompareOIDs(l.oids[idx], to) == -1 {
-		item := l.itemsByIndex[idx]
-		return l.oids[idx], item.Type, item.Value, nil
+// view returns the items, that serve the request of the provided context, and
+// a function that must be called, once the items are no longer used. In snapshot
+// mode, these are the (immutable) items of the session's snapshot.
+func (l *ListHandler) view(ctx context.Context) (*value.Tree[*ListItem], func()) {
+	if l.SnapshotTimeout <= 0 {
+		l.mu.RLock()
+		return l.items, l.mu.RUnlock
 	}
 
-	return nil, pdu.VariableTypeNoSuchObject, nil, nil
+	sessionID, now := SessionID(ctx), time.Now()
+	// requests without a transaction (e.g. direct calls) are not pinned
+	transactionID, pinned := ctx.Value(transactionIDKey{}).(uint32)
+	l.mu.RLock()
+	snapshot := l.snapshots[sessionID]
+	l.mu.RUnlock()
+	if snapshot != nil && snapshot.use(transactionID, pinned, now, l.SnapshotTimeout) {
+		return snapshot.items, func() {}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if snapshot := l.snapshots[sessionID]; snapshot != nil && snapshot.use(transactionID, pinned, now, l.SnapshotTimeout) {
+		return snapshot.items, func() {}
+	}
+	if l.snapshots == nil {
+		l.snapshots = make(map[uint32]*listSnapshot)
+	}
+	for id, snapshot := range l.snapshots {
+		if now.UnixNano() >= snapshot.expires.Load() {
+			delete(l.snapshots, id)
+		}
+	}
+	snapshot = &listSnapshot{items: l.items}
+	snapshot.transactionID.Store(transactionID)
+	snapshot.expires.Store(now.Add(l.SnapshotTimeout).UnixNano())
+	l.snapshots[sessionID] = snapshot
+	l.shared = true
+	return snapshot.items, func() {}
+}
+
+// tree returns the items for modification and creates them if needed. Items
+// shared with a snapshot are cloned first, which copies only the modified paths
+// of the tree. It must be called with the write lock held.
+func (l *ListHandler) tree() *value.Tree[*ListItem] {
+	if l.items == nil {
+		l.items = &value.Tree[*ListItem]{}
+	} else if l.shared {
+		l.items = l.items.Clone()
+		l.shared = false
+	}
+	return l.items
 }
@@ -0,0 +1,74 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package agentx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Olian04/go-agentx/pdu"
+	"github.com/Olian04/go-agentx/value"
+)
+
+func TestListHandlerSnapshotWalk(t *testing.T) {
+	const timeout = 200 * time.Millisecond
+	lh := &ListHandler{SnapshotTimeout: timeout}
+	entry := value.MustParseOID("1.3.6.1.4.1.45995.3.1.1")
+	end := value.MustParseOID("1.3.6.1.4.1.45996")
+	setRows := func(rows int) {
+		for row := uint32(1); row <= uint32(rows); row++ {
+			lh.Set(entry.Append(1, row), 0, value.Integer32(row))
+			lh.Set(entry.Append(2, row), 0, value.OctetString("row"))
+		}
+	}
+	request := func(transactionID uint32) context.Context {
+		return withTransactionID(withSessionID(context.Background(), 1), transactionID)
+	}
+	// walk returns the number of rows per column. Every GetNext is a transaction
+	// of its own, like the requests of a walk.
+	walk := func(transactionID uint32, step func(int)) map[uint32]int {
+		rows := map[uint32]int{}
+		oid := entry
+		for i := 0; ; i++ {
+			var err error
+			oid, _, _, err = lh.GetNext(request(transactionID+uint32(i)), oid, false, end)
+			require.NoError(t, err)
+			if oid == nil {
+				return rows
+			}
+			rows[oid[len(entry)]]++
+			step(i)
+		}
+	}
+
+	setRows(3)
+	// the walk takes longer than the timeout and the table grows during it
+	rows := walk(1, func(i int) {
+		if i == 0 {
+			setRows(4)
+		}
+		time.Sleep(timeout / 5)
+	})
+	assert.Equal(t, map[uint32]int{1: 3, 2: 3}, rows)
+
+	time.Sleep(timeout + timeout/2)
+	rows = walk(100, func(int) {})
+	assert.Equal(t, map[uint32]int{1: 4, 2: 4}, rows)
+
+	// a transaction keeps its snapshot after the expiry
+	setRows(5)
+	_, typ, _, err := lh.Get(request(200), entry.Append(1, 5))
+	require.NoError(t, err)
+	assert.Equal(t, pdu.VariableTypeNoSuchObject, typ)
+	time.Sleep(timeout + timeout/2)
+	_, typ, _, _ = lh.Get(request(200), entry.Append(1, 5))
+	assert.Equal(t, pdu.VariableTypeNoSuchObject, typ)
+	_, _, v, _ := lh.Get(request(201), entry.Append(1, 5))
+	assert.Equal(t, value.Integer32(5), v)
+}
@@ -5,8 +5,12 @@
 package agentx_test
 
 import (
+	"context"
+	"fmt"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -37,6 +41,8 @@ func TestListHandler(t *testing.T) {
 	i4.Type = pdu.VariableTypeOctetString
 	i4.Value = "test7"
 
+	lh.AddObject("1.3.6.1.4.1.45995.3.9")
+
 	session, err := e.client.Session(value.MustParseOID("1.3.6.1.4.1.45995"), "test client", lh)
 	require.NoError(t, err)
 	defer session.Close()
@@ -54,6 +60,10 @@ func TestListHandler(t *testing.T) {
 		assert.Equal(t,
 			".1.3.6.1.4.1.45995.3.2 = No Such Object available on this agent at this OID",
 			SNMPGet(t, "1.3.6.1.4.1.45995.3.2"))
+
+		assert.Equal(t,
+			".1.3.6.1.4.1.45995.3.9.1 = No Such Instance currently exists at this OID",
+			SNMPGet(t, "1.3.6.1.4.1.45995.3.9.1"))
 	})
 
 	t.Run("Get (multiple OIDs)", func(t *testing.T) {
@@ -136,6 +146,135 @@ func TestListHandler(t *testing.T) {
 	})
 }
 
+func TestListHandlerMutation(t *testing.T) {
+	ctx := context.Background()
+	lh := &agentx.ListHandler{}
+	end := value.MustParseOID("1.3.6.1.4.1.45996")
+
+	_, typ, _, err := lh.Get(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.1"))
+	require.NoError(t, err)
+	assert.Equal(t, pdu.VariableTypeNoSuchObject, typ)
+
+	lh.Set(value.MustParseOID("1.3.6.1.4.1.45995.3.1"), pdu.VariableTypeOctetString, "test")
+	lh.Set(value.MustParseOID("1.3.6.1.4.1.45995.3.2"), 0, value.Counter32(5))
+	oid, typ, v, err := lh.Get(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.2"))
+	require.NoError(t, err)
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.3.2"), oid)
+	assert.Equal(t, pdu.VariableTypeCounter32, typ)
+	assert.Equal(t, value.Counter32(5), v)
+
+	assert.True(t, lh.Remove(value.MustParseOID("1.3.6.1.4.1.45995.3.1")))
+	assert.False(t, lh.Remove(value.MustParseOID("1.3.6.1.4.1.45995.3.1")))
+	oid, _, _, err = lh.GetNext(ctx, value.MustParseOID("1.3.6.1.4.1.45995"), false, end)
+	require.NoError(t, err)
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.3.2"), oid)
+
+	require.NoError(t, lh.Replace(map[string]agentx.ListItem{
+		"1.3.6.1.4.1.45995.3.5": {Type: pdu.VariableTypeInteger, Value: int32(5)},
+	}))
+	oid, _, v, err = lh.GetNext(ctx, value.MustParseOID("1.3.6.1.4.1.45995"), false, end)
+	require.NoError(t, err)
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.3.5"), oid)
+	assert.Equal(t, int32(5), v)
+	oid, _, _, _ = lh.GetNext(ctx, oid, false, end)
+	assert.Nil(t, oid)
+
+	assert.Error(t, lh.Replace(map[string]agentx.ListItem{"x": {}}))
+}
+
+func TestListHandlerObjects(t *testing.T) {
+	ctx := context.Background()
+	lh := &agentx.ListHandler{}
+	lh.AddObject("1.3.6.1.4.1.45995.3.1.1.2")
+	lh.Set(value.MustParseOID("1.3.6.1.4.1.45995.3.1.1.2.1"), pdu.VariableTypeOctetString, "lo")
+
+	oid, typ, v, err := lh.Get(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.1.1.2.1"))
+	require.NoError(t, err)
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.3.1.1.2.1"), oid)
+	assert.Equal(t, pdu.VariableTypeOctetString, typ)
+	assert.Equal(t, "lo", v)
+
+	for missing, expected := range map[string]pdu.VariableType{
+		"1.3.6.1.4.1.45995.3.1.1.2.2":   pdu.VariableTypeNoSuchInstance,
+		"1.3.6.1.4.1.45995.3.1.1.2.2.5": pdu.VariableTypeNoSuchInstance,
+		"1.3.6.1.4.1.45995.3.1.1.2":     pdu.VariableTypeNoSuchObject,
+		"1.3.6.1.4.1.45995.3.1.1.3.1":   pdu.VariableTypeNoSuchObject,
+	} {
+		oid, typ, _, err := lh.Get(ctx, value.MustParseOID(missing))
+		require.NoError(t, err)
+		assert.Nil(t, oid, missing)
+		assert.Equal(t, expected, typ, missing)
+	}
+
+	// declared objects are kept on replace
+	require.NoError(t, lh.Replace(map[string]agentx.ListItem{}))
+	_, typ, _, _ = lh.Get(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.1.1.2.1"))
+	assert.Equal(t, pdu.VariableTypeNoSuchInstance, typ)
+}
+
+func TestListHandlerConcurrentUpdates(t *testing.T) {
+	for _, timeout := range []time.Duration{0, time.Microsecond} {
+		t.Run(fmt.Sprintf("SnapshotTimeout=%s", timeout), func(t *testing.T) {
+			ctx := context.Background()
+			lh := &agentx.ListHandler{SnapshotTimeout: timeout}
+			base := value.MustParseOID("1.3.6.1.4.1.45995.3")
+			end := value.MustParseOID("1.3.6.1.4.1.45996")
+
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 1000; i++ {
+					lh.Set(base.Append(uint32(i%10)), 0, value.Counter32(i))
+					if i%7 == 0 {
+						lh.Remove(base.Append(uint32(i % 10)))
+					}
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 1000; i++ {
+					oid := base
+					for oid != nil {
+						oid, _, _, _ = lh.GetNext(ctx, oid, false, end)
+					}
+				}
+			}()
+			wg.Wait()
+		})
+	}
+}
+
+func TestListHandlerSnapshot(t *testing.T) {
+	ctx := context.Background()
+	lh := &agentx.ListHandler{SnapshotTimeout: 100 * time.Millisecond}
+	base := value.MustParseOID("1.3.6.1.4.1.45995.3")
+	end := value.MustParseOID("1.3.6.1.4.1.45996")
+	lh.Set(base.Append(1), 0, value.Counter32(1))
+	lh.Set(base.Append(2), 0, value.Counter32(1))
+
+	oid, _, v, err := lh.GetNext(ctx, base, false, end)
+	require.NoError(t, err)
+	assert.Equal(t, base.Append(1), oid)
+	assert.Equal(t, value.Counter32(1), v)
+
+	// updates during the walk are not visible
+	lh.Set(base.Append(2), 0, value.Counter32(2))
+	lh.Set(base.Append(3), 0, value.Counter32(2))
+	oid, _, v, _ = lh.GetNext(ctx, oid, false, end)
+	assert.Equal(t, base.Append(2), oid)
+	assert.Equal(t, value.Counter32(1), v)
+	oid, _, _, _ = lh.GetNext(ctx, oid, false, end)
+	assert.Nil(t, oid)
+
+	// the next snapshot contains the updates
+	time.Sleep(150 * time.Millisecond)
+	_, _, v, _ = lh.Get(ctx, base.Append(2))
+	assert.Equal(t, value.Counter32(2), v)
+	oid, _, _, _ = lh.GetNext(ctx, base.Append(2), false, end)
+	assert.Equal(t, base.Append(3), oid)
+}
+
 func BenchmarkListHandlerSNMPGet(b *testing.B) {
 	e := setUpTestEnvironment(b)
 
@@ -198,3 +337,33 @@ func BenchmarkListHandlerSNMPGetParallel(b *testing.B) {
 		}
 	})
 }
+
+func BenchmarkListHandlerBulkAdd(b *testing.B) {
+	oids := make([]string, 100000)
+	for i := range oids {
+		oids[i] = fmt.Sprintf("1.3.6.1.4.1.45995.3.%d.%d", i/1000, i%1000)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		lh := &agentx.ListHandler{}
+		for _, oid := range oids {
+			lh.Add(oid).Set(value.Integer32(1))
+		}
+	}
+}
+
+func BenchmarkListHandlerGetNextWalk(b *testing.B) {
+	lh := &agentx.ListHandler{}
+	for i := 0; i < 100000; i++ {
+		lh.Add(fmt.Sprintf("1.3.6.1.4.1.45995.3.%d.%d", i/1000, i%1000)).Set(value.Integer32(int32(i)))
+	}
+	ctx := context.Background()
+	from, to := value.MustParseOID("1.3.6.1.4.1.45995"), value.MustParseOID("1.3.6.1.4.1.45996")
+	b.ReportAllocs()
+	for b.Loop() {
+		oid := from
+		for oid != nil {
+			oid, _, _, _ = lh.GetNext(ctx, oid, false, to)
+		}
+	}
+}
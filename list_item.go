@@ -4,10 +4,19 @@
 
 package agentx
 
-import "github.com/Olian04/go-agentx/pdu"
+import (
+	"github.com/Olian04/go-agentx/pdu"
+	"github.com/Olian04/go-agentx/value"
+)
 
 // ListItem defines an item of the list handler.
 type ListItem struct {
 	Type  pdu.VariableType
 	Value interface{}
 }
+
+// Set sets the type and the value of the item to the provided typed value.
+func (i *ListItem) Set(v value.Value) {
+	i.Type = pdu.VariableType(v.Type())
+	i.Value = v
+}
@@ -19,26 +19,26 @@ func NewMulti(marshalers ...encoding.BinaryMarshaler) Multi {
 
 // MarshalBinary marshals all the binary marshalers and concatinates the results.
 func (m Multi) MarshalBinary() ([]byte, error) {
-	// First pass: marshal each part and compute total length
-	type part struct {
-		data []byte
-	}
-	parts := make([]part, 0, len(m))
-	total := 0
+	return m.AppendBinary(nil)
+}
+
+// AppendBinary appends the results of all the binary marshalers to b. Marshalers
+// that implement encoding.BinaryAppender append directly to b without an
+// intermediate allocation.
+func (m Multi) AppendBinary(b []byte) ([]byte, error) {
 	for _, marshaler := range m {
+		if appender, ok := marshaler.(encoding.BinaryAppender); ok {
+			var err error
+			if b, err = appender.AppendBinary(b); err != nil {
+				return nil, err
+			}
+			continue
+		}
 		data, err := marshaler.MarshalBinary()
 		if err != nil {
 			return nil, err
 		}
-		parts = append(parts, part{data: data})
-		total += len(data)
-	}
-	// Second pass: copy into a single buffer
-	result := make([]byte, total)
-	offset := 0
-	for _, p := range parts {
-		copy(result[offset:], p.data)
-		offset += len(p.data)
+		b = append(b, data...)
 	}
-	return result, nil
+	return b, nil
 }
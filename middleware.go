@@ -0,0 +1,178 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package agentx
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/Olian04/go-agentx/pdu"
+	"github.com/Olian04/go-agentx/value"
+)
+
+// Middleware wraps a handler with additional behaviour.
+type Middleware func(Handler) Handler
+
+// Chain wraps the handler with the provided middlewares. The first middleware
+// is the outermost one, so it sees the requests first.
+func Chain(handler Handler, middlewares ...Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// HandlerFuncs implements a Handler with the provided functions.
+type HandlerFuncs struct {
+	GetFunc     func(context.Context, value.OID) (value.OID, pdu.VariableType, any, error)
+	GetNextFunc func(context.Context, value.OID, bool, value.OID) (value.OID, pdu.VariableType, any, error)
+}
+
+// Get calls GetFunc.
+func (h HandlerFuncs) Get(ctx context.Context, oid value.OID) (value.OID, pdu.VariableType, any, error) {
+	return h.GetFunc(ctx, oid)
+}
+
+// GetNext calls GetNextFunc.
+func (h HandlerFuncs) GetNext(ctx context.Context, from value.OID, includeFrom bool, to value.OID) (value.OID, pdu.VariableType, any, error) {
+	return h.GetNextFunc(ctx, from, includeFrom, to)
+}
+
+// Logging returns a middleware that logs each request with the provided logger
+// at debug level. Failed requests are logged at error level.
+func Logging(logger *slog.Logger) Middleware {
+	log := func(ctx context.Context, msg string, start time.Time, request, result value.OID, err error) {
+		level := slog.LevelDebug
+		if err != nil {
+			level = slog.LevelError
+		}
+		if !logger.Enabled(ctx, level) {
+			return
+		}
+		attrs := []slog.Attr{
+			slog.Uint64("session_id", uint64(SessionID(ctx))),
+			slog.Uint64("transaction_id", uint64(TransactionID(ctx))),
+			slog.Uint64("packet_id", uint64(PacketID(ctx))),
+			slog.String("oid", request.String()),
+			slog.String("result", result.String()),
+			slog.Duration("duration", time.Since(start)),
+		}
+		if err != nil {
+			attrs = append(attrs, slog.Any("err", err))
+		}
+		logger.LogAttrs(ctx, level, msg, attrs...)
+	}
+
+	return func(next Handler) Handler {
+		return HandlerFuncs{
+			GetFunc: func(ctx context.Context, oid value.OID) (value.OID, pdu.VariableType, any, error) {
+				start := time.Now()
+				resultOID, t, v, err := next.Get(ctx, oid)
+				log(ctx, "get", start, oid, resultOID, err)
+				return resultOID, t, v, err
+			},
+			GetNextFunc: func(ctx context.Context, from value.OID, includeFrom bool, to value.OID) (value.OID, pdu.VariableType, any, error) {
+				start := time.Now()
+				resultOID, t, v, err := next.GetNext(ctx, from, includeFrom, to)
+				log(ctx, "get next", start, from, resultOID, err)
+				return resultOID, t, v, err
+			},
+		}
+	}
+}
+
+// Timeout returns a middleware that limits the duration of each request. The
+// context passed to the handler is cancelled after the timeout. If the handler
+// doesn't return in time, the request fails with context.DeadlineExceeded and
+// the result of the handler is discarded. A panic of the handler is raised again
+// in the calling goroutine, so the client recovers it for the variable, unless
+// the request has timed out already.
+func Timeout(timeout time.Duration) Middleware {
+	type result struct {
+		oid       value.OID
+		t         pdu.VariableType
+		v         any
+		err       error
+		recovered any
+	}
+	run := func(ctx context.Context, fn func(context.Context) result) (value.OID, pdu.VariableType, any, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		done := make(chan result, 1)
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					done <- result{recovered: r}
+				}
+			}()
+			done <- fn(ctx)
+		}()
+		select {
+		case r := <-done:
+			if r.recovered != nil {
+				panic(r.recovered)
+			}
+			return r.oid, r.t, r.v, r.err
+		case <-ctx.Done():
+			return nil, pdu.VariableTypeNull, nil, ctx.Err()
+		}
+	}
+
+	return func(next Handler) Handler {
+		return HandlerFuncs{
+			GetFunc: func(ctx context.Context, oid value.OID) (value.OID, pdu.VariableType, any, error) {
+				return run(ctx, func(ctx context.Context) result {
+					oid, t, v, err := next.Get(ctx, oid)
+					return result{oid: oid, t: t, v: v, err: err}
+				})
+			},
+			GetNextFunc: func(ctx context.Context, from value.OID, includeFrom bool, to value.OID) (value.OID, pdu.VariableType, any, error) {
+				return run(ctx, func(ctx context.Context) result {
+					oid, t, v, err := next.GetNext(ctx, from, includeFrom, to)
+					return result{oid: oid, t: t, v: v, err: err}
+				})
+			},
+		}
+	}
+}
+
+// Restrict returns a middleware that only serves the oids inside the provided
+// subtrees. Get requests for other oids are answered with noSuchObject and
+// GetNext requests skip them.
+func Restrict(subtrees ...value.OID) Middleware {
+	subtrees = slices.Clone(subtrees)
+	value.SortOIDs(subtrees)
+	allowed := func(oid value.OID) bool {
+		return slices.ContainsFunc(subtrees, oid.HasPrefix)
+	}
+
+	return func(next Handler) Handler {
+		return HandlerFuncs{
+			GetFunc: func(ctx context.Context, oid value.OID) (value.OID, pdu.VariableType, any, error) {
+				if !allowed(oid) {
+					return nil, pdu.VariableTypeNoSuchObject, nil, nil
+				}
+				return next.Get(ctx, oid)
+			},
+			GetNextFunc: func(ctx context.Context, from value.OID, includeFrom bool, to value.OID) (value.OID, pdu.VariableType, any, error) {
+				for {
+					oid, t, v, err := next.GetNext(ctx, from, includeFrom, to)
+					if err != nil || oid == nil || allowed(oid) {
+						return oid, t, v, err
+					}
+					// continue with the next allowed subtree
+					i := slices.IndexFunc(subtrees, func(subtree value.OID) bool { return subtree.Compare(oid) > 0 })
+					if i < 0 {
+						return nil, pdu.VariableTypeNoSuchObject, nil, nil
+					}
+					from, includeFrom = subtrees[i], true
+				}
+			},
+		}
+	}
+}
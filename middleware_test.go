@@ -0,0 +1,108 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package agentx_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Olian04/go-agentx"
+	"github.com/Olian04/go-agentx/pdu"
+	"github.com/Olian04/go-agentx/value"
+)
+
+func TestChain(t *testing.T) {
+	var order []string
+	middleware := func(name string) agentx.Middleware {
+		return func(next agentx.Handler) agentx.Handler {
+			return agentx.HandlerFuncs{
+				GetFunc: func(ctx context.Context, oid value.OID) (value.OID, pdu.VariableType, any, error) {
+					order = append(order, name)
+					return next.Get(ctx, oid)
+				},
+			}
+		}
+	}
+	lh := &agentx.ListHandler{}
+	lh.Set(value.MustParseOID("1.3.6.1.4.1.45995.3.1"), 0, value.Integer32(1))
+
+	handler := agentx.Chain(lh, middleware("first"), middleware("second"))
+	oid, _, v, err := handler.Get(context.Background(), value.MustParseOID("1.3.6.1.4.1.45995.3.1"))
+	require.NoError(t, err)
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.3.1"), oid)
+	assert.Equal(t, value.Integer32(1), v)
+	assert.Equal(t, []string{"first", "second"}, order)
+}
+
+func TestLogging(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	lh := &agentx.ListHandler{}
+	lh.Set(value.MustParseOID("1.3.6.1.4.1.45995.3.1"), 0, value.Integer32(1))
+
+	handler := agentx.Chain(lh, agentx.Logging(logger))
+	_, _, _, err := handler.GetNext(context.Background(), value.MustParseOID("1.3.6.1.4.1.45995"), false, value.MustParseOID("1.3.6.1.4.1.45996"))
+	require.NoError(t, err)
+	assert.Contains(t, buffer.String(), "msg=\"get next\"")
+	assert.Contains(t, buffer.String(), "oid=1.3.6.1.4.1.45995 result=1.3.6.1.4.1.45995.3.1")
+	assert.NotContains(t, buffer.String(), "err=")
+
+	buffer.Reset()
+	fh := &agentx.FuncHandler{}
+	fh.Add("1.3.6.1.4.1.45995.3.1", pdu.VariableTypeInteger, func(ctx context.Context) (any, error) {
+		return nil, errors.New("broken")
+	})
+	handler = agentx.Chain(fh, agentx.Logging(logger))
+	_, _, _, err = handler.Get(context.Background(), value.MustParseOID("1.3.6.1.4.1.45995.3.1"))
+	require.Error(t, err)
+	assert.Contains(t, buffer.String(), "level=ERROR msg=get")
+	assert.Contains(t, buffer.String(), "err=broken")
+}
+
+func TestTimeout(t *testing.T) {
+	fh := &agentx.FuncHandler{}
+	fh.Add("1.3.6.1.4.1.45995.3.1", pdu.VariableTypeInteger, func(ctx context.Context) (any, error) {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		return 1, nil
+	})
+	fh.Add("1.3.6.1.4.1.45995.3.2", pdu.VariableTypeInteger, func(ctx context.Context) (any, error) {
+		return 2, nil
+	})
+
+	handler := agentx.Chain(fh, agentx.Timeout(20*time.Millisecond))
+	_, _, _, err := handler.Get(context.Background(), value.MustParseOID("1.3.6.1.4.1.45995.3.1"))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	_, _, v, err := handler.Get(context.Background(), value.MustParseOID("1.3.6.1.4.1.45995.3.2"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, v)
+}
+
+func TestRestrict(t *testing.T) {
+	ctx := context.Background()
+	end := value.MustParseOID("1.3.6.1.4.1.45996")
+	lh := &agentx.ListHandler{}
+	for _, oid := range []string{"1.3.6.1.4.1.45995.3.1", "1.3.6.1.4.1.45995.4.1", "1.3.6.1.4.1.45995.4.2", "1.3.6.1.4.1.45995.5.1"} {
+		lh.Set(value.MustParseOID(oid), 0, value.Integer32(1))
+	}
+
+	handler := agentx.Chain(lh, agentx.Restrict(value.MustParseOID("1.3.6.1.4.1.45995.5"), value.MustParseOID("1.3.6.1.4.1.45995.3")))
+	oid, _, _, _ := handler.Get(ctx, value.MustParseOID("1.3.6.1.4.1.45995.4.1"))
+	assert.Nil(t, oid)
+	oid, _, _, _ = handler.Get(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.1"))
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.3.1"), oid)
+
+	oid, _, _, _ = handler.GetNext(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.1"), false, end)
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.5.1"), oid)
+	oid, _, _, _ = handler.GetNext(ctx, oid, false, end)
+	assert.Nil(t, oid)
+}
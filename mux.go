@@ -0,0 +1,125 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package agentx
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/Olian04/go-agentx/pdu"
+	"github.com/Olian04/go-agentx/value"
+)
+
+// Mux is a handler that routes requests to the handlers of non-overlapping
+// subtrees. A GetNext request, that reaches the end of a subtree, continues
+// with the start of the following subtree. All methods are safe for
+// concurrent use.
+//
+// Each subtree (see Subtrees) can be registered on the session, that uses
+// the mux as handler.
+type Mux struct {
+	mu sync.RWMutex
+	// routes are sorted by subtree and replaced on modification, so readers can
+	// use them without holding the lock
+	routes []muxRoute
+}
+
+type muxRoute struct {
+	subtree value.OID
+	handler Handler
+}
+
+// Handle routes requests for the provided subtree to the handler. It returns an
+// error, if the subtree overlaps with the subtree of another handler.
+func (m *Mux) Handle(subtree value.OID, handler Handler) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, route := range m.routes {
+		if subtree.HasPrefix(route.subtree) || route.subtree.HasPrefix(subtree) {
+			return fmt.Errorf("subtree %s overlaps with subtree %s", subtree, route.subtree)
+		}
+	}
+	i := sort.Search(len(m.routes), func(i int) bool {
+		return m.routes[i].subtree.Compare(subtree) > 0
+	})
+	m.routes = slices.Insert(slices.Clone(m.routes), i, muxRoute{subtree: subtree.Clone(), handler: handler})
+	return nil
+}
+
+// Remove removes the handler of the provided subtree. It returns true, if the
+// subtree had a handler.
+func (m *Mux) Remove(subtree value.OID) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := slices.IndexFunc(m.routes, func(route muxRoute) bool { return route.subtree.Equal(subtree) })
+	if i < 0 {
+		return false
+	}
+	m.routes = slices.Delete(slices.Clone(m.routes), i, i+1)
+	return true
+}
+
+// Subtrees returns the subtrees of all handlers in ascending order.
+func (m *Mux) Subtrees() []value.OID {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	subtrees := make([]value.OID, len(m.routes))
+	for i, route := range m.routes {
+		subtrees[i] = route.subtree.Clone()
+	}
+	return subtrees
+}
+
+// Get routes the request to the handler of the subtree, that contains the
+// provided oid.
+func (m *Mux) Get(ctx context.Context, oid value.OID) (value.OID, pdu.VariableType, any, error) {
+	routes := m.routesFrom(oid)
+	if len(routes) == 0 || !oid.HasPrefix(routes[0].subtree) {
+		return nil, pdu.VariableTypeNoSuchObject, nil, nil
+	}
+	return routes[0].handler.Get(ctx, oid)
+}
+
+// GetNext routes the request to the handler of the subtree, that contains the
+// provided oid, and continues with the handlers of the following subtrees until
+// a value is found.
+func (m *Mux) GetNext(ctx context.Context, from value.OID, includeFrom bool, to value.OID) (value.OID, pdu.VariableType, any, error) {
+	for _, route := range m.routesFrom(from) {
+		if !from.HasPrefix(route.subtree) {
+			// the oid is located before the subtree
+			from, includeFrom = route.subtree, true
+		}
+		if value.CompareOIDs(from, to) != -1 {
+			break
+		}
+
+		oid, t, v, err := route.handler.GetNext(ctx, from, includeFrom, to)
+		if err != nil {
+			return oid, t, v, err
+		}
+		if oid != nil && oid.HasPrefix(route.subtree) {
+			return oid, t, v, nil
+		}
+	}
+	return nil, pdu.VariableTypeNoSuchObject, nil, nil
+}
+
+// routesFrom returns the routes, whose subtree contains the provided oid or
+// follows it.
+func (m *Mux) routesFrom(oid value.OID) []muxRoute {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	i := sort.Search(len(m.routes), func(i int) bool {
+		return oid.HasPrefix(m.routes[i].subtree) || m.routes[i].subtree.Compare(oid) > 0
+	})
+	return m.routes[i:]
+}
@@ -0,0 +1,78 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package agentx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Olian04/go-agentx"
+	"github.com/Olian04/go-agentx/value"
+)
+
+func TestMux(t *testing.T) {
+	ctx := context.Background()
+	end := value.MustParseOID("1.3.6.1.4.1.45996")
+
+	scalars := &agentx.ListHandler{}
+	scalars.Set(value.MustParseOID("1.3.6.1.4.1.45995.3.1.0"), 0, value.Integer32(1))
+	scalars.Set(value.MustParseOID("1.3.6.1.4.1.45995.3.2.0"), 0, value.Integer32(2))
+	// outside of the subtree of the handler, must be ignored
+	scalars.Set(value.MustParseOID("1.3.6.1.4.1.45995.6.1.0"), 0, value.Integer32(9))
+	empty := &agentx.ListHandler{}
+	table := testTableHandler(testInterface{1, "lo", 0}, testInterface{2, "eth1", 1000})
+	table.Entry = value.MustParseOID("1.3.6.1.4.1.45995.5.1.1")
+
+	mux := &agentx.Mux{}
+	require.NoError(t, mux.Handle(value.MustParseOID("1.3.6.1.4.1.45995.5"), table))
+	require.NoError(t, mux.Handle(value.MustParseOID("1.3.6.1.4.1.45995.3"), scalars))
+	require.NoError(t, mux.Handle(value.MustParseOID("1.3.6.1.4.1.45995.4"), empty))
+	assert.Error(t, mux.Handle(value.MustParseOID("1.3.6.1.4.1.45995.3.1"), empty))
+	assert.Error(t, mux.Handle(value.MustParseOID("1.3.6.1.4.1.45995"), empty))
+	assert.Equal(t, []value.OID{
+		value.MustParseOID("1.3.6.1.4.1.45995.3"),
+		value.MustParseOID("1.3.6.1.4.1.45995.4"),
+		value.MustParseOID("1.3.6.1.4.1.45995.5"),
+	}, mux.Subtrees())
+
+	oid, _, v, err := mux.Get(ctx, value.MustParseOID("1.3.6.1.4.1.45995.5.1.1.2.2"))
+	require.NoError(t, err)
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.5.1.1.2.2"), oid)
+	assert.Equal(t, "eth1", v)
+	oid, _, _, _ = mux.Get(ctx, value.MustParseOID("1.3.6.1.4.1.45995.6.1.0"))
+	assert.Nil(t, oid)
+
+	var walked []string
+	from := value.MustParseOID("1.3.6.1.4.1")
+	for {
+		oid, _, _, err := mux.GetNext(ctx, from, false, end)
+		require.NoError(t, err)
+		if oid == nil {
+			break
+		}
+		walked = append(walked, oid.String())
+		from = oid
+	}
+	assert.Equal(t, []string{
+		"1.3.6.1.4.1.45995.3.1.0",
+		"1.3.6.1.4.1.45995.3.2.0",
+		"1.3.6.1.4.1.45995.5.1.1.1.1",
+		"1.3.6.1.4.1.45995.5.1.1.1.2",
+		"1.3.6.1.4.1.45995.5.1.1.2.1",
+		"1.3.6.1.4.1.45995.5.1.1.2.2",
+		"1.3.6.1.4.1.45995.5.1.1.3.2",
+	}, walked)
+
+	oid, _, _, _ = mux.GetNext(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.2.0"), false, value.MustParseOID("1.3.6.1.4.1.45995.5"))
+	assert.Nil(t, oid)
+
+	assert.True(t, mux.Remove(value.MustParseOID("1.3.6.1.4.1.45995.3")))
+	assert.False(t, mux.Remove(value.MustParseOID("1.3.6.1.4.1.45995.3")))
+	oid, _, _, _ = mux.GetNext(ctx, value.MustParseOID("1.3.6.1.4.1"), false, end)
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.5.1.1.1.1"), oid)
+}
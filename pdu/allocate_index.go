@@ -16,14 +16,20 @@ func (ai *AllocateIndex) Type() Type {
 
 // MarshalBinary returns the pdu packet as a slice of bytes.
 func (ai *AllocateIndex) MarshalBinary() ([]byte, error) {
-	data, err := ai.Variables.MarshalBinary()
-	if err != nil {
-		return nil, err
-	}
-	return data, nil
+	return ai.AppendBinary(make([]byte, 0, ai.ByteSize()))
+}
+
+// AppendBinary appends the encoded pdu packet to b and returns the extended slice.
+func (ai *AllocateIndex) AppendBinary(b []byte) ([]byte, error) {
+	return ai.Variables.AppendBinary(b)
+}
+
+// ByteSize returns the number of bytes, the packet would need in the encoded version.
+func (ai *AllocateIndex) ByteSize() int {
+	return ai.Variables.ByteSize()
 }
 
 // UnmarshalBinary sets the packet structure from the provided slice of bytes.
 func (ai *AllocateIndex) UnmarshalBinary(data []byte) error {
-	return nil
+	return ai.Variables.UnmarshalBinary(data)
 }
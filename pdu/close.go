@@ -16,11 +16,24 @@ func (c *Close) Type() Type {
 
 // MarshalBinary returns the pdu packet as a slice of bytes.
 func (c *Close) MarshalBinary() ([]byte, error) {
-	return []byte{byte(c.Reason), 0x00, 0x00, 0x00}, nil
+	return c.AppendBinary(make([]byte, 0, c.ByteSize()))
+}
+
+// AppendBinary appends the encoded pdu packet to b and returns the extended slice.
+func (c *Close) AppendBinary(b []byte) ([]byte, error) {
+	return append(b, byte(c.Reason), 0x00, 0x00, 0x00), nil
+}
+
+// ByteSize returns the number of bytes, the packet would need in the encoded version.
+func (c *Close) ByteSize() int {
+	return 4
 }
 
 // UnmarshalBinary sets the packet structure from the provided slice of bytes.
 func (c *Close) UnmarshalBinary(data []byte) error {
+	if err := checkSize(data, 4, "close"); err != nil {
+		return err
+	}
 	c.Reason = Reason(data[0])
 	return nil
 }
@@ -16,14 +16,20 @@ func (di *DeallocateIndex) Type() Type {
 
 // MarshalBinary returns the pdu packet as a slice of bytes.
 func (di *DeallocateIndex) MarshalBinary() ([]byte, error) {
-	data, err := di.Variables.MarshalBinary()
-	if err != nil {
-		return nil, err
-	}
-	return data, nil
+	return di.AppendBinary(make([]byte, 0, di.ByteSize()))
+}
+
+// AppendBinary appends the encoded pdu packet to b and returns the extended slice.
+func (di *DeallocateIndex) AppendBinary(b []byte) ([]byte, error) {
+	return di.Variables.AppendBinary(b)
+}
+
+// ByteSize returns the number of bytes, the packet would need in the encoded version.
+func (di *DeallocateIndex) ByteSize() int {
+	return di.Variables.ByteSize()
 }
 
 // UnmarshalBinary sets the packet structure from the provided slice of bytes.
 func (di *DeallocateIndex) UnmarshalBinary(data []byte) error {
-	return nil
+	return di.Variables.UnmarshalBinary(data)
 }
@@ -0,0 +1,145 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package pdu
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// Version defines the supported version of the AgentX protocol.
+	Version = 1
+
+	// DefaultMaxPayloadLength defines the maximum payload length that is accepted
+	// by a decoder, unless configured otherwise.
+	DefaultMaxPayloadLength = 1 << 20
+
+	// MinMaxPayloadLength defines the smallest maximum payload length, that can
+	// be configured. It leaves room for an open packet with the longest
+	// description.
+	MinMaxPayloadLength = 1 << 10
+)
+
+// The various decoding errors.
+var (
+	ErrUnsupportedVersion = errors.New("unsupported protocol version")
+	ErrUnsupportedType    = errors.New("unsupported packet type")
+	ErrInvalidLength      = errors.New("invalid payload length")
+	ErrPayloadTooLarge    = errors.New("payload too large")
+)
+
+// DecodeError is returned by the decoder, if a packet has been read completely
+// from the stream, but couldn't be decoded. The stream is still in sync, so
+// the decoding can continue with the next packet.
+type DecodeError struct {
+	Header *Header
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decode %s: %v", e.Header.Type, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Decoder reads and decodes packets from a stream.
+type Decoder struct {
+	r                io.Reader
+	header           [HeaderSize]byte
+	payload          []byte
+	maxPayloadLength uint32
+}
+
+// NewDecoder returns a new decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r, maxPayloadLength: DefaultMaxPayloadLength}
+}
+
+// SetMaxPayloadLength sets the maximum payload length of a packet. The payload
+// of larger packets is skipped and a DecodeError is returned for them.
+func (d *Decoder) SetMaxPayloadLength(value uint32) {
+	d.maxPayloadLength = value
+}
+
+// Decode reads the next packet from the stream. If the stream ends in front
+// of a packet, io.EOF is returned.
+func (d *Decoder) Decode() (*HeaderPacket, error) {
+	if _, err := io.ReadFull(d.r, d.header[:]); err != nil {
+		return nil, err
+	}
+	header := &Header{}
+	if err := header.UnmarshalBinary(d.header[:]); err != nil {
+		return nil, err
+	}
+	if header.PayloadLength > d.maxPayloadLength {
+		if _, err := io.CopyN(io.Discard, d.r, int64(header.PayloadLength)); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		err := fmt.Errorf("%w: %d bytes exceed the limit of %d bytes", ErrPayloadTooLarge, header.PayloadLength, d.maxPayloadLength)
+		return nil, &DecodeError{Header: header, Err: err}
+	}
+
+	length := int(header.PayloadLength)
+	if cap(d.payload) < length {
+		d.payload = make([]byte, length)
+	}
+	payload := d.payload[:length]
+	if _, err := io.ReadFull(d.r, payload); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	if header.Version != Version {
+		return nil, &DecodeError{Header: header, Err: fmt.Errorf("%w %d", ErrUnsupportedVersion, header.Version)}
+	}
+	if length%4 != 0 {
+		return nil, &DecodeError{Header: header, Err: fmt.Errorf("%w %d: must be a multiple of 4", ErrInvalidLength, length)}
+	}
+
+	packet := newPacket(header.Type)
+	if packet == nil {
+		return nil, &DecodeError{Header: header, Err: ErrUnsupportedType}
+	}
+	if err := packet.UnmarshalBinary(payload); err != nil {
+		return nil, &DecodeError{Header: header, Err: err}
+	}
+
+	return &HeaderPacket{Header: header, Packet: packet}, nil
+}
+
+// newPacket returns an empty packet of the provided type or nil, if the type
+// can't be decoded.
+func newPacket(t Type) Packet {
+	switch t {
+	case TypeOpen:
+		return &Open{}
+	case TypeClose:
+		return &Close{}
+	case TypeRegister:
+		return &Register{}
+	case TypeUnregister:
+		return &Unregister{}
+	case TypeGet:
+		return &Get{}
+	case TypeGetNext:
+		return &GetNext{}
+	case TypeIndexAllocate:
+		return &AllocateIndex{}
+	case TypeIndexDeallocate:
+		return &DeallocateIndex{}
+	case TypeResponse:
+		return &Response{}
+	}
+	return nil
+}
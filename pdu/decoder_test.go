@@ -0,0 +1,158 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package pdu_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Olian04/go-agentx/pdu"
+	"github.com/Olian04/go-agentx/value"
+)
+
+func encodePackets(tb testing.TB, hps ...*pdu.HeaderPacket) []byte {
+	buffer := &bytes.Buffer{}
+	encoder := pdu.NewEncoder(buffer)
+	for _, hp := range hps {
+		require.NoError(tb, encoder.Encode(hp))
+	}
+	return buffer.Bytes()
+}
+
+func TestEncoderDecoder(t *testing.T) {
+	open := &pdu.Open{}
+	open.Timeout.Duration = time.Minute
+	open.ID.SetIdentifier(value.MustParseOID("1.3.6.1.4.1.45995"))
+	open.Description.Text = "test client"
+
+	response := &pdu.Response{Error: pdu.ErrorProcessing, Index: 1}
+	response.Variables.Add(value.MustParseOID("1.3.6.1.4.1.45995.3.1"), pdu.VariableTypeOctetString, "test")
+
+	data := encodePackets(t,
+		&pdu.HeaderPacket{Header: &pdu.Header{SessionID: 1, PacketID: 2}, Packet: open},
+		&pdu.HeaderPacket{Header: &pdu.Header{SessionID: 1, PacketID: 3}, Packet: response},
+	)
+
+	decoder := pdu.NewDecoder(bytes.NewReader(data))
+
+	hp, err := decoder.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, pdu.TypeOpen, hp.Header.Type)
+	assert.Equal(t, uint32(2), hp.Header.PacketID)
+	assert.Equal(t, open, hp.Packet)
+
+	hp, err = decoder.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, pdu.TypeResponse, hp.Header.Type)
+	assert.Equal(t, uint32(3), hp.Header.PacketID)
+	assert.Equal(t, pdu.ErrorProcessing, hp.Packet.(*pdu.Response).Error)
+	assert.Equal(t, "test", hp.Packet.(*pdu.Response).Variables[0].Value)
+
+	_, err = decoder.Decode()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestDecoderErrors(t *testing.T) {
+	valid := encodePackets(t, &pdu.HeaderPacket{Header: &pdu.Header{}, Packet: &pdu.Close{Reason: pdu.ReasonShutdown}})
+
+	t.Run("Unsupported version", func(t *testing.T) {
+		data := append([]byte{}, valid...)
+		data[0] = 2
+		decoder := pdu.NewDecoder(bytes.NewReader(append(data, valid...)))
+
+		_, err := decoder.Decode()
+		assert.ErrorIs(t, err, pdu.ErrUnsupportedVersion)
+		var decodeErr *pdu.DecodeError
+		assert.ErrorAs(t, err, &decodeErr)
+
+		hp, err := decoder.Decode()
+		require.NoError(t, err)
+		assert.Equal(t, pdu.TypeClose, hp.Header.Type)
+	})
+
+	t.Run("Unsupported type", func(t *testing.T) {
+		data := append([]byte{}, valid...)
+		data[1] = byte(pdu.TypeNotify)
+		decoder := pdu.NewDecoder(bytes.NewReader(append(data, valid...)))
+
+		_, err := decoder.Decode()
+		assert.ErrorIs(t, err, pdu.ErrUnsupportedType)
+
+		hp, err := decoder.Decode()
+		require.NoError(t, err)
+		assert.Equal(t, pdu.TypeClose, hp.Header.Type)
+	})
+
+	t.Run("Invalid length", func(t *testing.T) {
+		data := append([]byte{}, valid...)
+		binary.LittleEndian.PutUint32(data[16:], 3)
+		decoder := pdu.NewDecoder(bytes.NewReader(data[:pdu.HeaderSize+3]))
+
+		_, err := decoder.Decode()
+		assert.ErrorIs(t, err, pdu.ErrInvalidLength)
+	})
+
+	t.Run("Payload too large", func(t *testing.T) {
+		response := &pdu.Response{}
+		response.Variables.Add(value.MustParseOID("1.3.6.1.4.1.45995.3.1"), pdu.VariableTypeOctetString, "test")
+		large := encodePackets(t, &pdu.HeaderPacket{Header: &pdu.Header{}, Packet: response})
+		decoder := pdu.NewDecoder(bytes.NewReader(append(large, valid...)))
+		decoder.SetMaxPayloadLength(16)
+
+		_, err := decoder.Decode()
+		assert.ErrorIs(t, err, pdu.ErrPayloadTooLarge)
+		var decodeErr *pdu.DecodeError
+		require.ErrorAs(t, err, &decodeErr)
+		assert.Equal(t, pdu.TypeResponse, decodeErr.Header.Type)
+
+		hp, err := decoder.Decode()
+		require.NoError(t, err)
+		assert.Equal(t, pdu.TypeClose, hp.Header.Type)
+	})
+
+	t.Run("Payload too large and truncated", func(t *testing.T) {
+		data := append([]byte{}, valid...)
+		binary.LittleEndian.PutUint32(data[16:], pdu.DefaultMaxPayloadLength+4)
+		decoder := pdu.NewDecoder(bytes.NewReader(data))
+
+		_, err := decoder.Decode()
+		assert.Equal(t, io.ErrUnexpectedEOF, err)
+	})
+
+	t.Run("Truncated payload", func(t *testing.T) {
+		decoder := pdu.NewDecoder(bytes.NewReader(valid[:len(valid)-1]))
+
+		_, err := decoder.Decode()
+		assert.Equal(t, io.ErrUnexpectedEOF, err)
+	})
+}
+
+func FuzzDecoder(f *testing.F) {
+	f.Add(encodePackets(f, &pdu.HeaderPacket{Header: &pdu.Header{}, Packet: &pdu.Close{Reason: pdu.ReasonShutdown}}))
+	for _, seed := range []string{"open", "response"} {
+		payload := seedPackets(f)[seed]
+		header, err := (&pdu.Header{Version: pdu.Version, Type: pdu.TypeResponse, PayloadLength: uint32(len(payload))}).MarshalBinary()
+		require.NoError(f, err)
+		f.Add(append(header, payload...))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		decoder := pdu.NewDecoder(bytes.NewReader(data))
+		for {
+			// Decoding may only continue after a decode error.
+			var decodeErr *pdu.DecodeError
+			if _, err := decoder.Decode(); err != nil && !errors.As(err, &decodeErr) {
+				return
+			}
+		}
+	})
+}
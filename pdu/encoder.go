@@ -0,0 +1,37 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package pdu
+
+import (
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Encoder encodes packets and writes them to a stream. The packets are encoded
+// into a buffer, that is re-used for all packets written by the encoder.
+type Encoder struct {
+	w   io.Writer
+	buf []byte
+}
+
+// NewEncoder returns a new encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes the provided packet to the stream. The version, type and payload
+// length fields of the packet header are set by the encoder.
+func (e *Encoder) Encode(hp *HeaderPacket) error {
+	data, err := hp.AppendBinary(slices.Grow(e.buf[:0], hp.ByteSize()))
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", hp.Packet.Type(), err)
+	}
+	e.buf = data
+	if _, err := e.w.Write(data); err != nil {
+		return err
+	}
+	return nil
+}
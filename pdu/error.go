@@ -9,6 +9,24 @@ import "fmt"
 // The various pdu packet errors.
 const (
 	ErrorNone                  Error = 0
+	ErrorTooBig                Error = 1
+	ErrorNoSuchName            Error = 2
+	ErrorBadValue              Error = 3
+	ErrorReadOnly              Error = 4
+	ErrorGenErr                Error = 5
+	ErrorNoAccess              Error = 6
+	ErrorWrongType             Error = 7
+	ErrorWrongLength           Error = 8
+	ErrorWrongEncoding         Error = 9
+	ErrorWrongValue            Error = 10
+	ErrorNoCreation            Error = 11
+	ErrorInconsistentValue     Error = 12
+	ErrorResourceUnavailable   Error = 13
+	ErrorCommitFailed          Error = 14
+	ErrorUndoFailed            Error = 15
+	ErrorAuthorizationError    Error = 16
+	ErrorNotWritable           Error = 17
+	ErrorInconsistentName      Error = 18
 	ErrorOpenFailed            Error = 256
 	ErrorNotOpen               Error = 257
 	ErrorIndexWrongType        Error = 258
@@ -24,13 +42,55 @@ const (
 	ErrorProcessing            Error = 268
 )
 
-// Error defines a pdu packet error.
+// Error defines a pdu packet error. It implements the error interface, so
+// handlers can return it (or wrap it) to answer a request with the error.
 type Error uint16
 
+// Error returns the name of the error.
+func (e Error) Error() string {
+	return e.String()
+}
+
 func (e Error) String() string {
 	switch e {
 	case ErrorNone:
 		return "ErrorNone"
+	case ErrorTooBig:
+		return "ErrorTooBig"
+	case ErrorNoSuchName:
+		return "ErrorNoSuchName"
+	case ErrorBadValue:
+		return "ErrorBadValue"
+	case ErrorReadOnly:
+		return "ErrorReadOnly"
+	case ErrorGenErr:
+		return "ErrorGenErr"
+	case ErrorNoAccess:
+		return "ErrorNoAccess"
+	case ErrorWrongType:
+		return "ErrorWrongType"
+	case ErrorWrongLength:
+		return "ErrorWrongLength"
+	case ErrorWrongEncoding:
+		return "ErrorWrongEncoding"
+	case ErrorWrongValue:
+		return "ErrorWrongValue"
+	case ErrorNoCreation:
+		return "ErrorNoCreation"
+	case ErrorInconsistentValue:
+		return "ErrorInconsistentValue"
+	case ErrorResourceUnavailable:
+		return "ErrorResourceUnavailable"
+	case ErrorCommitFailed:
+		return "ErrorCommitFailed"
+	case ErrorUndoFailed:
+		return "ErrorUndoFailed"
+	case ErrorAuthorizationError:
+		return "ErrorAuthorizationError"
+	case ErrorNotWritable:
+		return "ErrorNotWritable"
+	case ErrorInconsistentName:
+		return "ErrorInconsistentName"
 	case ErrorOpenFailed:
 		return "ErrorOpenFailed"
 	case ErrorNotOpen:
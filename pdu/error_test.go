@@ -0,0 +1,27 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package pdu_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Olian04/go-agentx/pdu"
+)
+
+func TestError(t *testing.T) {
+	err := fmt.Errorf("access denied: %w", pdu.ErrorNoAccess)
+	assert.Equal(t, "access denied: ErrorNoAccess", err.Error())
+	assert.ErrorIs(t, err, pdu.ErrorNoAccess)
+
+	var pduErr pdu.Error
+	assert.True(t, errors.As(err, &pduErr))
+	assert.Equal(t, pdu.ErrorNoAccess, pduErr)
+
+	assert.Equal(t, "ErrorUnknown (1000)", pdu.Error(1000).Error())
+}
@@ -0,0 +1,204 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package pdu_test
+
+import (
+	"encoding"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Olian04/go-agentx/pdu"
+	"github.com/Olian04/go-agentx/value"
+)
+
+func seedVariables(tb testing.TB) []byte {
+	variables := pdu.Variables{}
+	variables.Add(value.MustParseOID("1.3.6.1.4.1.45995.3.1"), pdu.VariableTypeInteger, int32(-123))
+	variables.Add(value.MustParseOID("1.3.6.1.4.1.45995.3.2"), pdu.VariableTypeOctetString, "echo test")
+	variables.Add(value.MustParseOID("1.3.6.1.4.1.45995.3.3"), pdu.VariableTypeNull, nil)
+	variables.Add(value.MustParseOID("1.3.6.1.4.1.45995.3.4"), pdu.VariableTypeObjectIdentifier, "1.3.6.1.4.1.45995.1.5")
+	variables.Add(value.MustParseOID("1.3.6.1.4.1.45995.3.5"), pdu.VariableTypeIPAddress, net.IP{10, 10, 10, 10})
+	variables.Add(value.MustParseOID("1.3.6.1.4.1.45995.3.6"), pdu.VariableTypeCounter32, uint32(123))
+	variables.Add(value.MustParseOID("1.3.6.1.4.1.45995.3.7"), pdu.VariableTypeGauge32, uint32(123))
+	variables.Add(value.MustParseOID("1.3.6.1.4.1.45995.3.8"), pdu.VariableTypeTimeTicks, 123*time.Second)
+	variables.Add(value.MustParseOID("1.3.6.1.4.1.45995.3.9"), pdu.VariableTypeOpaque, []byte{1, 2, 3})
+	variables.Add(value.MustParseOID("1.3.6.1.4.1.45995.3.10"), pdu.VariableTypeCounter64, uint64(12345678901234567890))
+	variables.Add(value.MustParseOID("1.3.6.1.4.1.45995.3.11"), pdu.VariableTypeEndOfMIBView, nil)
+	data, err := variables.MarshalBinary()
+	require.NoError(tb, err)
+	return data
+}
+
+func seedRanges(tb testing.TB) []byte {
+	oid := func(text string, include bool) []byte {
+		o := pdu.ObjectIdentifier{}
+		o.SetIdentifier(value.MustParseOID(text))
+		o.SetInclude(include)
+		data, err := o.MarshalBinary()
+		require.NoError(tb, err)
+		return data
+	}
+	var data []byte
+	data = append(data, oid("1.3.6.1.4.1.45995.3.1", true)...)
+	data = append(data, oid("1.3.6.1.4.1.45995.4", false)...)
+	data = append(data, oid("1.3.6.1.4.1.45995.3.3", false)...)
+	data = append(data, oid("", false)...)
+	return data
+}
+
+func seedPackets(tb testing.TB) map[string][]byte {
+	marshal := func(m encoding.BinaryMarshaler) []byte {
+		data, err := m.MarshalBinary()
+		require.NoError(tb, err)
+		return data
+	}
+
+	open := &pdu.Open{}
+	open.Timeout.Duration = time.Minute
+	open.ID.SetIdentifier(value.MustParseOID("1.3.6.1.4.1.45995"))
+	open.Description.Text = "test client"
+
+	register := &pdu.Register{}
+	register.Timeout.Priority = 127
+	register.Subtree.SetIdentifier(value.MustParseOID("1.3.6.1.4.1.45995.3"))
+
+	variables := seedVariables(tb)
+	response := append([]byte{0x10, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, variables...)
+
+	return map[string][]byte{
+		"header":    marshal(&pdu.Header{Version: 1, Type: pdu.TypeGet, SessionID: 1, PayloadLength: 8}),
+		"open":      marshal(open),
+		"close":     marshal(&pdu.Close{Reason: pdu.ReasonShutdown}),
+		"register":  marshal(register),
+		"variables": variables,
+		"ranges":    seedRanges(tb),
+		"response":  response,
+	}
+}
+
+func fuzzUnmarshal(f *testing.F, newUnmarshaler func() encoding.BinaryUnmarshaler, seeds ...string) {
+	packets := seedPackets(f)
+	for _, seed := range seeds {
+		data := packets[seed]
+		f.Add(data)
+		f.Add(data[:len(data)/2])
+	}
+	f.Add([]byte{})
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_ = newUnmarshaler().UnmarshalBinary(data)
+	})
+}
+
+func FuzzHeader(f *testing.F) {
+	fuzzUnmarshal(f, func() encoding.BinaryUnmarshaler { return &pdu.Header{} }, "header")
+}
+
+func FuzzObjectIdentifier(f *testing.F) {
+	fuzzUnmarshal(f, func() encoding.BinaryUnmarshaler { return &pdu.ObjectIdentifier{} }, "ranges")
+}
+
+func FuzzOctetString(f *testing.F) {
+	fuzzUnmarshal(f, func() encoding.BinaryUnmarshaler { return &pdu.OctetString{} }, "open")
+}
+
+func FuzzVariable(f *testing.F) {
+	fuzzUnmarshal(f, func() encoding.BinaryUnmarshaler { return &pdu.Variable{} }, "variables")
+}
+
+func FuzzVariables(f *testing.F) {
+	fuzzUnmarshal(f, func() encoding.BinaryUnmarshaler { return &pdu.Variables{} }, "variables")
+}
+
+func FuzzRanges(f *testing.F) {
+	fuzzUnmarshal(f, func() encoding.BinaryUnmarshaler { return &pdu.Ranges{} }, "ranges")
+}
+
+func FuzzOpen(f *testing.F) {
+	fuzzUnmarshal(f, func() encoding.BinaryUnmarshaler { return &pdu.Open{} }, "open")
+}
+
+func FuzzClose(f *testing.F) {
+	fuzzUnmarshal(f, func() encoding.BinaryUnmarshaler { return &pdu.Close{} }, "close")
+}
+
+func FuzzRegister(f *testing.F) {
+	fuzzUnmarshal(f, func() encoding.BinaryUnmarshaler { return &pdu.Register{} }, "register")
+}
+
+func FuzzUnregister(f *testing.F) {
+	fuzzUnmarshal(f, func() encoding.BinaryUnmarshaler { return &pdu.Unregister{} }, "register")
+}
+
+func FuzzGet(f *testing.F) {
+	fuzzUnmarshal(f, func() encoding.BinaryUnmarshaler { return &pdu.Get{} }, "ranges")
+}
+
+func FuzzGetNext(f *testing.F) {
+	fuzzUnmarshal(f, func() encoding.BinaryUnmarshaler { return &pdu.GetNext{} }, "ranges")
+}
+
+func FuzzResponse(f *testing.F) {
+	fuzzUnmarshal(f, func() encoding.BinaryUnmarshaler { return &pdu.Response{} }, "response")
+}
+
+func FuzzAllocateIndex(f *testing.F) {
+	fuzzUnmarshal(f, func() encoding.BinaryUnmarshaler { return &pdu.AllocateIndex{} }, "variables")
+}
+
+func FuzzDeallocateIndex(f *testing.F) {
+	fuzzUnmarshal(f, func() encoding.BinaryUnmarshaler { return &pdu.DeallocateIndex{} }, "variables")
+}
+
+func TestUnmarshalTruncated(t *testing.T) {
+	for name, data := range seedPackets(t) {
+		t.Run(name, func(t *testing.T) {
+			for size := range data {
+				assert.NotPanics(t, func() {
+					_ = (&pdu.Response{}).UnmarshalBinary(data[:size])
+					_ = (&pdu.Open{}).UnmarshalBinary(data[:size])
+					_ = (&pdu.Get{}).UnmarshalBinary(data[:size])
+				})
+			}
+		})
+	}
+}
+
+func TestUnmarshalResponseRoundTrip(t *testing.T) {
+	data := seedPackets(t)["response"]
+
+	response := &pdu.Response{}
+	require.NoError(t, response.UnmarshalBinary(data))
+	require.Len(t, response.Variables, 11)
+
+	assert.Equal(t, int32(-123), response.Variables[0].Value)
+	assert.Equal(t, "echo test", response.Variables[1].Value)
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.1.5"), response.Variables[3].Value)
+	assert.Equal(t, net.IP{10, 10, 10, 10}, response.Variables[4].Value)
+	assert.Equal(t, 123*time.Second, response.Variables[7].Value)
+	assert.Equal(t, []byte{1, 2, 3}, response.Variables[8].Value)
+	assert.Equal(t, uint64(12345678901234567890), response.Variables[9].Value)
+	assert.Equal(t, pdu.VariableTypeEndOfMIBView, response.Variables[10].Type)
+}
+
+func TestUnmarshalResponseShort(t *testing.T) {
+	data := seedPackets(t)["response"]
+
+	err := (&pdu.Response{}).UnmarshalBinary(data[:len(data)-2])
+	assert.ErrorIs(t, err, pdu.ErrShortPacket)
+
+	err = (&pdu.Response{}).UnmarshalBinary(data[:5])
+	assert.ErrorIs(t, err, pdu.ErrShortPacket)
+}
+
+func TestUnmarshalOctetStringOverlong(t *testing.T) {
+	err := (&pdu.OctetString{}).UnmarshalBinary([]byte{0xff, 0xff, 0xff, 0xff, 'a', 'b', 'c', 'd'})
+	assert.ErrorIs(t, err, pdu.ErrShortPacket)
+}
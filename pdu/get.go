@@ -16,8 +16,17 @@ func (g *Get) Type() Type {
 
 // MarshalBinary returns the pdu packet as a slice of bytes.
 func (g *Get) MarshalBinary() ([]byte, error) {
-	// Subagents do not send Get PDUs; master sends them. Keep empty.
-	return []byte{}, nil
+	return g.AppendBinary(make([]byte, 0, g.ByteSize()))
+}
+
+// AppendBinary appends the encoded pdu packet to b and returns the extended slice.
+func (g *Get) AppendBinary(b []byte) ([]byte, error) {
+	return g.SearchRanges.AppendBinary(b)
+}
+
+// ByteSize returns the number of bytes, the packet would need in the encoded version.
+func (g *Get) ByteSize() int {
+	return g.SearchRanges.ByteSize()
 }
 
 // UnmarshalBinary sets the packet structure from the provided slice of bytes.
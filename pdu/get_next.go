@@ -16,7 +16,17 @@ func (g *GetNext) Type() Type {
 
 // MarshalBinary returns the pdu packet as a slice of bytes.
 func (g *GetNext) MarshalBinary() ([]byte, error) {
-	return []byte{}, nil
+	return g.AppendBinary(make([]byte, 0, g.ByteSize()))
+}
+
+// AppendBinary appends the encoded pdu packet to b and returns the extended slice.
+func (g *GetNext) AppendBinary(b []byte) ([]byte, error) {
+	return g.SearchRanges.AppendBinary(b)
+}
+
+// ByteSize returns the number of bytes, the packet would need in the encoded version.
+func (g *GetNext) ByteSize() int {
+	return g.SearchRanges.ByteSize()
 }
 
 // UnmarshalBinary sets the packet structure from the provided slice of bytes.
@@ -6,7 +6,6 @@ package pdu
 
 import (
 	"encoding/binary"
-	"fmt"
 )
 
 const (
@@ -27,22 +26,24 @@ type Header struct {
 
 // MarshalBinary returns the pdu header as a slice of bytes.
 func (h *Header) MarshalBinary() ([]byte, error) {
-	result := make([]byte, HeaderSize)
-	result[0] = h.Version
-	result[1] = byte(h.Type)
-	result[2] = byte(h.Flags)
-	// result[3] is reserved padding byte (0x00)
-	binary.LittleEndian.PutUint32(result[4:], h.SessionID)
-	binary.LittleEndian.PutUint32(result[8:], h.TransactionID)
-	binary.LittleEndian.PutUint32(result[12:], h.PacketID)
-	binary.LittleEndian.PutUint32(result[16:], h.PayloadLength)
-	return result, nil
+	return h.AppendBinary(make([]byte, 0, HeaderSize))
+}
+
+// AppendBinary appends the encoded pdu header to b and returns the extended slice.
+func (h *Header) AppendBinary(b []byte) ([]byte, error) {
+	// the fourth byte is a reserved padding byte (0x00)
+	b = append(b, h.Version, byte(h.Type), byte(h.Flags), 0x00)
+	b = binary.LittleEndian.AppendUint32(b, h.SessionID)
+	b = binary.LittleEndian.AppendUint32(b, h.TransactionID)
+	b = binary.LittleEndian.AppendUint32(b, h.PacketID)
+	b = binary.LittleEndian.AppendUint32(b, h.PayloadLength)
+	return b, nil
 }
 
 // UnmarshalBinary sets the header structure from the provided slice of bytes.
 func (h *Header) UnmarshalBinary(data []byte) error {
-	if len(data) < HeaderSize {
-		return fmt.Errorf("not enough bytes (%d) to unmarshal the header (%d)", len(data), HeaderSize)
+	if err := checkSize(data, HeaderSize, "header"); err != nil {
+		return err
 	}
 
 	h.Version, h.Type, h.Flags = data[0], Type(data[1]), Flags(data[2])
@@ -5,8 +5,8 @@
 package pdu
 
 import (
-	"fmt"
 	"encoding/binary"
+	"fmt"
 )
 
 // HeaderPacket defines a container structure for a header and a packet.
@@ -17,29 +17,32 @@ type HeaderPacket struct {
 
 // MarshalBinary returns the pdu packet as a slice of bytes.
 func (hp *HeaderPacket) MarshalBinary() ([]byte, error) {
-	payloadBytes, err := hp.Packet.MarshalBinary()
+	return hp.AppendBinary(make([]byte, 0, hp.ByteSize()))
+}
+
+// AppendBinary appends the encoded header and packet to b and returns the extended slice.
+// The version, type and payload length fields of the header are set accordingly.
+func (hp *HeaderPacket) AppendBinary(b []byte) ([]byte, error) {
+	start := len(b)
+
+	hp.Header.Version = Version
+	hp.Header.Type = hp.Packet.Type()
+	b, _ = hp.Header.AppendBinary(b)
+
+	b, err := hp.Packet.AppendBinary(b)
 	if err != nil {
 		return nil, err
 	}
 
-	hp.Header.Version = 1
-	hp.Header.Type = hp.Packet.Type()
-	hp.Header.PayloadLength = uint32(len(payloadBytes))
-
-	// Single allocation: header (20 bytes) + payload
-	result := make([]byte, HeaderSize+len(payloadBytes))
-	// Write header inline (mirror Header.MarshalBinary but avoid extra alloc)
-	result[0] = hp.Header.Version
-	result[1] = byte(hp.Header.Type)
-	result[2] = byte(hp.Header.Flags)
-	// result[3] reserved
-	binary.LittleEndian.PutUint32(result[4:], hp.Header.SessionID)
-	binary.LittleEndian.PutUint32(result[8:], hp.Header.TransactionID)
-	binary.LittleEndian.PutUint32(result[12:], hp.Header.PacketID)
-	binary.LittleEndian.PutUint32(result[16:], hp.Header.PayloadLength)
-	// Copy payload
-	copy(result[HeaderSize:], payloadBytes)
-	return result, nil
+	// Patch the payload length into the already written header
+	hp.Header.PayloadLength = uint32(len(b) - start - HeaderSize)
+	binary.LittleEndian.PutUint32(b[start+16:], hp.Header.PayloadLength)
+	return b, nil
+}
+
+// ByteSize returns the number of bytes, the header and packet would need in the encoded version.
+func (hp *HeaderPacket) ByteSize() int {
+	return HeaderSize + hp.Packet.ByteSize()
 }
 
 func (hp *HeaderPacket) String() string {
@@ -0,0 +1,134 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package pdu_test
+
+import (
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Olian04/go-agentx/pdu"
+	"github.com/Olian04/go-agentx/value"
+)
+
+// largeResponse returns a response, like it would be sent for a bulk request
+// over a table with the provided number of rows.
+func largeResponse(rows int) *pdu.HeaderPacket {
+	response := &pdu.Response{}
+	response.Variables = make(pdu.Variables, 0, rows*3)
+	for row := uint32(1); row <= uint32(rows); row++ {
+		response.Variables.Add(value.OID{1, 3, 6, 1, 2, 1, 2, 2, 1, 2, row}, pdu.VariableTypeOctetString, "GigabitEthernet0/1")
+		response.Variables.Add(value.OID{1, 3, 6, 1, 2, 1, 2, 2, 1, 10, row}, pdu.VariableTypeCounter32, uint32(row*1000))
+		response.Variables.Add(value.OID{1, 3, 6, 1, 2, 1, 31, 1, 1, 1, 6, row}, pdu.VariableTypeCounter64, uint64(row)*1e12)
+	}
+	return &pdu.HeaderPacket{Header: &pdu.Header{SessionID: 1, TransactionID: 2, PacketID: 3}, Packet: response}
+}
+
+// marshalPerField encodes the response like MarshalBinary did before
+// AppendBinary: every field is marshalled into its own slice and the parts are
+// concatenated afterwards.
+func marshalPerField(hp *pdu.HeaderPacket) ([]byte, error) {
+	response := hp.Packet.(*pdu.Response)
+	parts := make([][]byte, 0, len(response.Variables)+1)
+	size := 8
+	for i := range response.Variables {
+		part, err := response.Variables[i].MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+		size += len(part)
+	}
+
+	hp.Header.Version = pdu.Version
+	hp.Header.Type = response.Type()
+	hp.Header.PayloadLength = uint32(size)
+	header, err := hp.Header.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]byte, 0, len(header)+size)
+	result = append(result, header...)
+	result = binary.LittleEndian.AppendUint32(result, uint32(response.UpTime.Seconds()*100))
+	result = binary.LittleEndian.AppendUint16(result, uint16(response.Error))
+	result = binary.LittleEndian.AppendUint16(result, response.Index)
+	for _, part := range parts {
+		result = append(result, part...)
+	}
+	return result, nil
+}
+
+func TestHeaderPacketAppendBinary(t *testing.T) {
+	hp := largeResponse(10)
+
+	data, err := hp.MarshalBinary()
+	require.NoError(t, err)
+	assert.Len(t, data, hp.ByteSize())
+	assert.Equal(t, uint32(len(data)-pdu.HeaderSize), hp.Header.PayloadLength)
+
+	prefix := []byte{0xde, 0xad}
+	appended, err := hp.AppendBinary(prefix)
+	require.NoError(t, err)
+	assert.Equal(t, prefix, appended[:2])
+	assert.Equal(t, data, appended[2:])
+
+	perField, err := marshalPerField(hp)
+	require.NoError(t, err)
+	assert.Equal(t, data, perField)
+
+	header := &pdu.Header{}
+	require.NoError(t, header.UnmarshalBinary(data))
+	response := &pdu.Response{}
+	require.NoError(t, response.UnmarshalBinary(data[pdu.HeaderSize:]))
+	assert.Equal(t, hp.Packet, response)
+}
+
+// BenchmarkHeaderPacketMarshalPerField is the baseline for the benchmarks below,
+// it allocates a slice per field.
+func BenchmarkHeaderPacketMarshalPerField(b *testing.B) {
+	hp := largeResponse(1000)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := marshalPerField(hp); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkHeaderPacketMarshalBinary(b *testing.B) {
+	hp := largeResponse(1000)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := hp.MarshalBinary(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkHeaderPacketAppendBinary(b *testing.B) {
+	hp := largeResponse(1000)
+	buf := make([]byte, 0, hp.ByteSize())
+	b.ReportAllocs()
+	for b.Loop() {
+		var err error
+		if buf, err = hp.AppendBinary(buf[:0]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncoder(b *testing.B) {
+	hp := largeResponse(1000)
+	encoder := pdu.NewEncoder(io.Discard)
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := encoder.Encode(hp); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
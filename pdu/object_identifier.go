@@ -6,7 +6,9 @@ package pdu
 
 import (
 	"encoding/binary"
+	"fmt"
 
+	"github.com/Olian04/go-agentx/internal/wire"
 	"github.com/Olian04/go-agentx/value"
 )
 
@@ -15,6 +17,10 @@ const (
 	INCLUDE_FALSE = 0x00
 )
 
+// MaxSubidentifiers defines the maximum number of subidentifiers in an
+// object identifier (RFC 2741, section 5.1).
+const MaxSubidentifiers = value.MaxSubidentifiers
+
 // ObjectIdentifier defines the pdu object identifier packet.
 type ObjectIdentifier struct {
 	Prefix         uint8
@@ -38,16 +44,10 @@ func (o *ObjectIdentifier) GetInclude() bool {
 
 // SetIdentifier set the subidentifiers by the provided oid string.
 func (o *ObjectIdentifier) SetIdentifier(oid value.OID) {
-	// AgentX OID wire format uses a 'Prefix' to compress 1.3.6.1.<prefix>
-	// The Subidentifiers must NOT include the 1.3.6.1.<prefix> part when Prefix is set.
-	o.Prefix = 0
-	if len(oid) > 4 && oid[0] == 1 && oid[1] == 3 && oid[2] == 6 && oid[3] == 1 {
-		// Set Prefix to the 5th arc and strip the first 5 arcs from subids
-		o.Prefix = uint8(oid[4])
-		oid = oid[5:]
-	}
-	o.Subidentifiers = make([]uint32, len(oid))
-	copy(o.Subidentifiers, oid)
+	var subids []uint32
+	o.Prefix, subids = wire.CompressOID(oid)
+	o.Subidentifiers = make([]uint32, len(subids))
+	copy(o.Subidentifiers, subids)
 }
 
 // GetIdentifier returns the identifier as an oid string.
@@ -61,26 +61,31 @@ func (o *ObjectIdentifier) GetIdentifier() value.OID {
 
 // ByteSize returns the number of bytes, the binding would need in the encoded version.
 func (o *ObjectIdentifier) ByteSize() int {
-	return 4 + len(o.Subidentifiers)*4
+	return wire.OIDSize(len(o.Subidentifiers))
 }
 
 // MarshalBinary returns the pdu packet as a slice of bytes.
 func (o *ObjectIdentifier) MarshalBinary() ([]byte, error) {
-	count := len(o.Subidentifiers)
-	result := make([]byte, 4+count*4)
-	result[0] = byte(count)
-	result[1] = o.Prefix
-	result[2] = o.Include
-	// result[3] reserved (0x00)
-	for i, sub := range o.Subidentifiers {
-		binary.LittleEndian.PutUint32(result[4+i*4:], sub)
-	}
-	return result, nil
+	return o.AppendBinary(make([]byte, 0, o.ByteSize()))
+}
+
+// AppendBinary appends the encoded object identifier to b and returns the extended slice.
+func (o *ObjectIdentifier) AppendBinary(b []byte) ([]byte, error) {
+	return wire.AppendOID(b, o.Prefix, o.Include, o.Subidentifiers)
 }
 
 // UnmarshalBinary sets the packet structure from the provided slice of bytes.
 func (o *ObjectIdentifier) UnmarshalBinary(data []byte) error {
+	if err := checkSize(data, 4, "object identifier header"); err != nil {
+		return err
+	}
 	count := int(data[0])
+	if count > MaxSubidentifiers {
+		return fmt.Errorf("object identifier has %d subidentifiers, at most %d are allowed", count, MaxSubidentifiers)
+	}
+	if err := checkSize(data, 4+count*4, "object identifier"); err != nil {
+		return err
+	}
 	o.Prefix = data[1]
 	o.Include = data[2]
 
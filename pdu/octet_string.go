@@ -6,6 +6,9 @@ package pdu
 
 import (
 	"encoding/binary"
+	"fmt"
+
+	"github.com/Olian04/go-agentx/internal/wire"
 )
 
 // OctetString defines the pdu description packet.
@@ -15,18 +18,38 @@ type OctetString struct {
 
 // MarshalBinary returns the pdu packet as a slice of bytes.
 func (o *OctetString) MarshalBinary() ([]byte, error) {
-	l := len(o.Text)
-	pad := (4 - (l % 4)) & 3
-	result := make([]byte, 4+l+pad)
-	binary.LittleEndian.PutUint32(result[0:], uint32(l))
-	copy(result[4:], o.Text)
-	// padding bytes are already zeroed by make
-	return result, nil
+	return o.AppendBinary(make([]byte, 0, o.ByteSize()))
+}
+
+// AppendBinary appends the encoded octet string to b and returns the extended slice.
+func (o *OctetString) AppendBinary(b []byte) ([]byte, error) {
+	return wire.AppendOctets(b, o.Text), nil
 }
 
 // UnmarshalBinary sets the packet structure from the provided slice of bytes.
 func (o *OctetString) UnmarshalBinary(data []byte) error {
+	if err := checkSize(data, 4, "octet string length"); err != nil {
+		return err
+	}
 	length := binary.LittleEndian.Uint32(data[0:])
+	if uint64(length) > uint64(len(data)-4) {
+		return fmt.Errorf("%w: octet string announces %d bytes, got %d", ErrShortPacket, length, len(data)-4)
+	}
 	o.Text = string(data[4 : 4+length])
 	return nil
 }
+
+// Bytes returns the octet string as a slice of bytes.
+func (o *OctetString) Bytes() []byte {
+	return []byte(o.Text)
+}
+
+// SetBytes sets the octet string to the provided bytes.
+func (o *OctetString) SetBytes(b []byte) {
+	o.Text = string(b)
+}
+
+// ByteSize returns the number of bytes, the octet string would need in the encoded version.
+func (o *OctetString) ByteSize() int {
+	return wire.OctetsSize(len(o.Text))
+}
@@ -4,10 +4,6 @@
 
 package pdu
 
-import (
-	"github.com/Olian04/go-agentx/marshaler"
-)
-
 // Open defines a pdu open packet.
 type Open struct {
 	Timeout     Timeout
@@ -22,17 +18,36 @@ func (o *Open) Type() Type {
 
 // MarshalBinary returns the pdu packet as a slice of bytes.
 func (o *Open) MarshalBinary() ([]byte, error) {
-	combined := marshaler.NewMulti(&o.Timeout, &o.ID, &o.Description)
+	return o.AppendBinary(make([]byte, 0, o.ByteSize()))
+}
 
-	combinedBytes, err := combined.MarshalBinary()
+// AppendBinary appends the encoded pdu packet to b and returns the extended slice.
+func (o *Open) AppendBinary(b []byte) ([]byte, error) {
+	b, _ = o.Timeout.AppendBinary(b)
+	b, err := o.ID.AppendBinary(b)
 	if err != nil {
 		return nil, err
 	}
+	return o.Description.AppendBinary(b)
+}
 
-	return combinedBytes, nil
+// ByteSize returns the number of bytes, the packet would need in the encoded version.
+func (o *Open) ByteSize() int {
+	return o.Timeout.ByteSize() + o.ID.ByteSize() + o.Description.ByteSize()
 }
 
 // UnmarshalBinary sets the packet structure from the provided slice of bytes.
 func (o *Open) UnmarshalBinary(data []byte) error {
+	if err := o.Timeout.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	offset := 4
+	if err := o.ID.UnmarshalBinary(data[offset:]); err != nil {
+		return err
+	}
+	offset += o.ID.ByteSize()
+	if err := o.Description.UnmarshalBinary(data[offset:]); err != nil {
+		return err
+	}
 	return nil
 }
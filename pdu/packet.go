@@ -4,11 +4,31 @@
 
 package pdu
 
-import "encoding"
+import (
+	"encoding"
+	"errors"
+	"fmt"
+)
+
+// ErrShortPacket is returned (wrapped) by the unmarshal functions, if the provided
+// data is shorter than the structure that should be decoded from it.
+var ErrShortPacket = errors.New("short packet")
 
 // Packet defines a general interface for a pdu packet.
 type Packet interface {
 	TypeOwner
 	encoding.BinaryMarshaler
+	encoding.BinaryAppender
 	encoding.BinaryUnmarshaler
+
+	// ByteSize returns the number of bytes, the packet would need in the encoded version.
+	ByteSize() int
+}
+
+// checkSize returns an error if data holds less than size bytes.
+func checkSize(data []byte, size int, name string) error {
+	if len(data) < size {
+		return fmt.Errorf("%w: %s needs %d bytes, got %d", ErrShortPacket, name, size, len(data))
+	}
+	return nil
 }
@@ -6,6 +6,8 @@ package pdu
 
 import (
 	"fmt"
+
+	"github.com/Olian04/go-agentx/internal/wire"
 )
 
 // Range defines the pdu search range packet.
@@ -21,8 +23,17 @@ func (r *Range) ByteSize() int {
 
 // MarshalBinary returns the pdu packet as a slice of bytes.
 func (r *Range) MarshalBinary() ([]byte, error) {
-	r.To.SetInclude(false)
-	return []byte{}, nil
+	return r.AppendBinary(make([]byte, 0, r.ByteSize()))
+}
+
+// AppendBinary appends the encoded search range to b and returns the extended slice.
+// The include field of the end of the range is always encoded as false.
+func (r *Range) AppendBinary(b []byte) ([]byte, error) {
+	b, err := r.From.AppendBinary(b)
+	if err != nil {
+		return nil, err
+	}
+	return wire.AppendOID(b, r.To.Prefix, INCLUDE_FALSE, r.To.Subidentifiers)
 }
 
 // UnmarshalBinary sets the packet structure from the provided slice of bytes.
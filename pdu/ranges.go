@@ -4,12 +4,31 @@
 
 package pdu
 
+import "fmt"
+
 // Ranges defines the pdu search range list packet.
 type Ranges []Range
 
 // MarshalBinary returns the pdu packet as a slice of bytes.
 func (r *Ranges) MarshalBinary() ([]byte, error) {
-	return []byte{}, nil
+	return r.AppendBinary(make([]byte, 0, r.ByteSize()))
+}
+
+// AppendBinary appends the encoded search ranges to b and returns the extended slice.
+func (r *Ranges) AppendBinary(b []byte) ([]byte, error) {
+	for i := range *r {
+		b, _ = (*r)[i].AppendBinary(b)
+	}
+	return b, nil
+}
+
+// ByteSize returns the number of bytes, the ranges would need in the encoded version.
+func (r *Ranges) ByteSize() int {
+	size := 0
+	for i := range *r {
+		size += (*r)[i].ByteSize()
+	}
+	return size
 }
 
 // UnmarshalBinary sets the packet structure from the provided slice of bytes.
@@ -17,9 +36,9 @@ func (r *Ranges) UnmarshalBinary(data []byte) error {
 	// Pre-size allocation by scanning encoded sizes once
 	count := 0
 	for offset := 0; offset < len(data); {
-		size, ok := encodedRangeSize(data[offset:])
-		if !ok {
-			break
+		size, err := encodedRangeSize(data[offset:])
+		if err != nil {
+			return fmt.Errorf("search range %d: %w", count+1, err)
 		}
 		offset += size
 		count++
@@ -28,7 +47,7 @@ func (r *Ranges) UnmarshalBinary(data []byte) error {
 	for offset := 0; offset < len(data); {
 		rng := Range{}
 		if err := rng.UnmarshalBinary(data[offset:]); err != nil {
-			return err
+			return fmt.Errorf("search range %d: %w", len(*r)+1, err)
 		}
 		*r = append(*r, rng)
 		offset += rng.ByteSize()
@@ -38,27 +57,26 @@ func (r *Ranges) UnmarshalBinary(data []byte) error {
 
 // encodedOIDSize returns the encoded size of an ObjectIdentifier starting at data.
 // Format: 1 byte count, 1 byte prefix, 1 byte include, 1 byte reserved, then count*4 bytes subids.
-func encodedOIDSize(data []byte) (int, bool) {
-	if len(data) < 4 {
-		return 0, false
+func encodedOIDSize(data []byte) (int, error) {
+	if err := checkSize(data, 4, "object identifier header"); err != nil {
+		return 0, err
 	}
-	count := int(data[0])
-	size := 4 + count*4
-	if len(data) < size {
-		return 0, false
+	size := 4 + int(data[0])*4
+	if err := checkSize(data, size, "object identifier"); err != nil {
+		return 0, err
 	}
-	return size, true
+	return size, nil
 }
 
 // encodedRangeSize returns the encoded size of a Range (From OID + To OID).
-func encodedRangeSize(data []byte) (int, bool) {
-	fromSize, ok := encodedOIDSize(data)
-	if !ok {
-		return 0, false
+func encodedRangeSize(data []byte) (int, error) {
+	fromSize, err := encodedOIDSize(data)
+	if err != nil {
+		return 0, err
 	}
-	toSize, ok := encodedOIDSize(data[fromSize:])
-	if !ok {
-		return 0, false
+	toSize, err := encodedOIDSize(data[fromSize:])
+	if err != nil {
+		return 0, err
 	}
-	return fromSize + toSize, true
+	return fromSize + toSize, nil
 }
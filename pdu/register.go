@@ -4,10 +4,6 @@
 
 package pdu
 
-import (
-	"github.com/Olian04/go-agentx/marshaler"
-)
-
 // Register defines the pdu register packet.
 type Register struct {
 	Timeout Timeout
@@ -21,17 +17,28 @@ func (r *Register) Type() Type {
 
 // MarshalBinary returns the pdu packet as a slice of bytes.
 func (r *Register) MarshalBinary() ([]byte, error) {
-	combined := marshaler.NewMulti(&r.Timeout, &r.Subtree)
+	return r.AppendBinary(make([]byte, 0, r.ByteSize()))
+}
 
-	combinedBytes, err := combined.MarshalBinary()
-	if err != nil {
-		return nil, err
-	}
+// AppendBinary appends the encoded pdu packet to b and returns the extended slice.
+func (r *Register) AppendBinary(b []byte) ([]byte, error) {
+	b, _ = r.Timeout.AppendBinary(b)
+	return r.Subtree.AppendBinary(b)
+}
 
-	return combinedBytes, nil
+// ByteSize returns the number of bytes, the packet would need in the encoded version.
+func (r *Register) ByteSize() int {
+	return r.Timeout.ByteSize() + r.Subtree.ByteSize()
 }
 
 // UnmarshalBinary sets the packet structure from the provided slice of bytes.
 func (r *Register) UnmarshalBinary(data []byte) error {
+	if err := r.Timeout.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	// An optional range upper bound following the subtree is not supported.
+	if err := r.Subtree.UnmarshalBinary(data[4:]); err != nil {
+		return err
+	}
 	return nil
 }
@@ -4,7 +4,6 @@
 
 package pdu
 
-
 import (
 	"encoding/binary"
 	"time"
@@ -25,22 +24,29 @@ func (r *Response) Type() Type {
 
 // MarshalBinary returns the pdu packet as a slice of bytes.
 func (r *Response) MarshalBinary() ([]byte, error) {
+	return r.AppendBinary(make([]byte, 0, r.ByteSize()))
+}
+
+// AppendBinary appends the encoded pdu packet to b and returns the extended slice.
+func (r *Response) AppendBinary(b []byte) ([]byte, error) {
 	// AgentX encodes sysUpTime in hundredths of a second (centiseconds)
 	upTime := uint32(r.UpTime.Seconds() * 100)
-	vBytes, err := r.Variables.MarshalBinary()
-	if err != nil {
-		return nil, err
-	}
-	result := make([]byte, 8+len(vBytes))
-	binary.LittleEndian.PutUint32(result[0:], upTime)
-	binary.LittleEndian.PutUint16(result[4:], uint16(r.Error))
-	binary.LittleEndian.PutUint16(result[6:], r.Index)
-	copy(result[8:], vBytes)
-	return result, nil
+	b = binary.LittleEndian.AppendUint32(b, upTime)
+	b = binary.LittleEndian.AppendUint16(b, uint16(r.Error))
+	b = binary.LittleEndian.AppendUint16(b, r.Index)
+	return r.Variables.AppendBinary(b)
+}
+
+// ByteSize returns the number of bytes, the packet would need in the encoded version.
+func (r *Response) ByteSize() int {
+	return 8 + r.Variables.ByteSize()
 }
 
 // UnmarshalBinary sets the packet structure from the provided slice of bytes.
 func (r *Response) UnmarshalBinary(data []byte) error {
+	if err := checkSize(data, 8, "response"); err != nil {
+		return err
+	}
 	upTime := binary.LittleEndian.Uint32(data[0:])
 	// Convert centiseconds to duration
 	r.UpTime = time.Duration(upTime) * time.Second / 100
@@ -14,11 +14,24 @@ type Timeout struct {
 
 // MarshalBinary returns the pdu packet as a slice of bytes.
 func (t *Timeout) MarshalBinary() ([]byte, error) {
-	return []byte{byte(t.Duration.Seconds()), t.Priority, 0x00, 0x00}, nil
+	return t.AppendBinary(make([]byte, 0, t.ByteSize()))
+}
+
+// AppendBinary appends the encoded timeout to b and returns the extended slice.
+func (t *Timeout) AppendBinary(b []byte) ([]byte, error) {
+	return append(b, byte(t.Duration.Seconds()), t.Priority, 0x00, 0x00), nil
+}
+
+// ByteSize returns the number of bytes, the timeout would need in the encoded version.
+func (t *Timeout) ByteSize() int {
+	return 4
 }
 
 // UnmarshalBinary sets the packet structure from the provided slice of bytes.
 func (t *Timeout) UnmarshalBinary(data []byte) error {
+	if err := checkSize(data, 4, "timeout"); err != nil {
+		return err
+	}
 	t.Duration = time.Duration(data[0]) * time.Second
 	t.Priority = data[1]
 	return nil
@@ -4,10 +4,6 @@
 
 package pdu
 
-import (
-	"github.com/Olian04/go-agentx/marshaler"
-)
-
 // Unregister defines the pdu unregister packet.
 type Unregister struct {
 	Timeout Timeout
@@ -21,17 +17,28 @@ func (u *Unregister) Type() Type {
 
 // MarshalBinary returns the pdu packet as a slice of bytes.
 func (u *Unregister) MarshalBinary() ([]byte, error) {
-	combined := marshaler.NewMulti(&u.Timeout, &u.Subtree)
+	return u.AppendBinary(make([]byte, 0, u.ByteSize()))
+}
 
-	combinedBytes, err := combined.MarshalBinary()
-	if err != nil {
-		return nil, err
-	}
+// AppendBinary appends the encoded pdu packet to b and returns the extended slice.
+func (u *Unregister) AppendBinary(b []byte) ([]byte, error) {
+	b, _ = u.Timeout.AppendBinary(b)
+	return u.Subtree.AppendBinary(b)
+}
 
-	return combinedBytes, nil
+// ByteSize returns the number of bytes, the packet would need in the encoded version.
+func (u *Unregister) ByteSize() int {
+	return u.Timeout.ByteSize() + u.Subtree.ByteSize()
 }
 
 // UnmarshalBinary sets the packet structure from the provided slice of bytes.
 func (u *Unregister) UnmarshalBinary(data []byte) error {
+	if err := u.Timeout.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	// An optional range upper bound following the subtree is not supported.
+	if err := u.Subtree.UnmarshalBinary(data[4:]); err != nil {
+		return err
+	}
 	return nil
 }
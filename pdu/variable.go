@@ -6,25 +6,42 @@ package pdu
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
 	"net"
+	"net/netip"
 	"time"
 
+	"github.com/Olian04/go-agentx/internal/wire"
 	"github.com/Olian04/go-agentx/value"
 )
 
-// Variable defines the pdu varbind packet.
+// ErrInvalidValue is returned (wrapped) on marshaling, if the value of a variable
+// doesn't match the variable type.
+var ErrInvalidValue = errors.New("invalid value")
+
+// Variable defines the pdu varbind packet. The Value is either a value.Value
+// of the variable type, or the Go representation of the variable type (int32,
+// string or []byte, value.OID, net.IP or netip.Addr, uint32, time.Duration, []byte
+// or uint64). IP addresses are sent as 4-byte IPv4 addresses, IPv6 addresses are
+// rejected; use value.InetAddress for IPv6 capable objects.
+// Decoded octet strings are provided as string, see Bytes for a byte slice view.
 type Variable struct {
 	Type  VariableType
 	Name  ObjectIdentifier
 	Value interface{}
 }
 
-// Set sets the variable.
-func (v *Variable) Set(oid value.OID, t VariableType, value interface{}) {
+// Set sets the variable. If the provided type is zero and the value is a
+// value.Value, the type is taken from the value.
+func (v *Variable) Set(oid value.OID, t VariableType, val interface{}) {
+	if typed, ok := val.(value.Value); ok && t == 0 {
+		t = VariableType(typed.Type())
+	}
 	v.Name.SetIdentifier(oid)
 	v.Type = t
-	v.Value = value
+	v.Value = val
 }
 
 // ByteSize returns the number of bytes the variable would occupy when marshaled.
@@ -32,45 +49,33 @@ func (v *Variable) ByteSize() int {
 	size := 4 // varbind header: type + 3 reserved bytes
 	size += v.Name.ByteSize()
 
+	if v.Type.isException() {
+		// no payload
+		return size
+	}
+	if typed, ok := v.Value.(value.Value); ok {
+		return size + typed.ByteSize()
+	}
+
 	switch v.Type {
-	case VariableTypeInteger:
+	case VariableTypeInteger, VariableTypeCounter32, VariableTypeGauge32, VariableTypeTimeTicks:
 		size += 4
 	case VariableTypeOctetString:
-		text := v.Value.(string)
-		l := len(text)
-		pad := (4 - (l % 4)) & 3
-		size += 4 + l + pad
-	case VariableTypeNull, VariableTypeNoSuchObject, VariableTypeNoSuchInstance, VariableTypeEndOfMIBView:
-		// no payload
-	case VariableTypeObjectIdentifier:
-		// v.Value is string during marshal path, value.OID during unmarshal path
-		var oidVal value.OID
-		switch vv := v.Value.(type) {
+		switch octets := v.Value.(type) {
 		case string:
-			parsed, _ := value.ParseOID(vv)
-			oidVal = parsed
-		case value.OID:
-			oidVal = vv
-		default:
-			oidVal = nil
+			size += wire.OctetsSize(len(octets))
+		case []byte:
+			size += wire.OctetsSize(len(octets))
 		}
-		oi := &ObjectIdentifier{}
-		oi.SetIdentifier(oidVal)
-		size += oi.ByteSize()
+	case VariableTypeObjectIdentifier:
+		text, _ := v.Value.(string)
+		oid, _ := value.ParseOID(text)
+		size += oid.ByteSize()
 	case VariableTypeIPAddress:
-		ip := v.Value.(net.IP)
-		l := len(ip)
-		pad := (4 - (l % 4)) & 3
-		size += 4 + l + pad
-	case VariableTypeCounter32, VariableTypeGauge32:
-		size += 4
-	case VariableTypeTimeTicks:
-		size += 4
+		size += wire.OctetsSize(4)
 	case VariableTypeOpaque:
-		data := v.Value.([]byte)
-		l := len(data)
-		pad := (4 - (l % 4)) & 3
-		size += 4 + l + pad
+		data, _ := v.Value.([]byte)
+		size += wire.OctetsSize(len(data))
 	case VariableTypeCounter64:
 		size += 8
 	default:
@@ -82,174 +87,214 @@ func (v *Variable) ByteSize() int {
 
 // MarshalBinary returns the pdu packet as a slice of bytes.
 func (v *Variable) MarshalBinary() ([]byte, error) {
-	total := v.ByteSize()
-	result := make([]byte, total)
-	if _, err := v.MarshalTo(result); err != nil {
-		return nil, err
-	}
-	return result, nil
+	return v.AppendBinary(make([]byte, 0, v.ByteSize()))
 }
 
 // MarshalTo writes the variable into dst and returns bytes written.
-// dst must have capacity >= v.ByteSize().
+// It returns io.ErrShortBuffer, if the capacity of dst is less than v.ByteSize().
 func (v *Variable) MarshalTo(dst []byte) (int, error) {
-	offset := 0
+	if cap(dst) < v.ByteSize() {
+		return 0, io.ErrShortBuffer
+	}
+	result, err := v.AppendBinary(dst[:0])
+	if err != nil {
+		return 0, err
+	}
+	return len(result), nil
+}
 
+// AppendBinary appends the encoded variable to b and returns the extended slice.
+func (v *Variable) AppendBinary(b []byte) ([]byte, error) {
 	// VarBind header
-	dst[offset] = byte(v.Type)
-	dst[offset+1] = 0x00
-	dst[offset+2] = 0x00
-	dst[offset+3] = 0x00
-	offset += 4
+	b = append(b, byte(v.Type), 0x00, 0x00, 0x00)
 
-	// Name (inline ObjectIdentifier marshal)
-	nameCount := len(v.Name.Subidentifiers)
-	dst[offset] = byte(nameCount)
-	dst[offset+1] = v.Name.Prefix
-	dst[offset+2] = v.Name.Include
-	// dst[offset+3] reserved
-	offset += 4
-	for i, sub := range v.Name.Subidentifiers {
-		binary.LittleEndian.PutUint32(dst[offset+i*4:], sub)
+	// Name
+	b, err := v.Name.AppendBinary(b)
+	if err != nil {
+		return nil, err
 	}
-	offset += nameCount * 4
 
 	// Value
+	if v.Type.isException() {
+		// no payload
+		return b, nil
+	}
+	if typed, ok := v.Value.(value.Value); ok {
+		if VariableType(typed.Type()) != v.Type {
+			return nil, v.invalidValueError()
+		}
+		return typed.AppendBinary(b)
+	}
+
 	switch v.Type {
 	case VariableTypeInteger:
-		value := uint32(v.Value.(int32))
-		binary.LittleEndian.PutUint32(dst[offset:], value)
-		offset += 4
+		value, ok := v.Value.(int32)
+		if !ok {
+			return nil, v.invalidValueError()
+		}
+		b = binary.LittleEndian.AppendUint32(b, uint32(value))
 	case VariableTypeOctetString:
-		text := v.Value.(string)
-		l := len(text)
-		pad := (4 - (l % 4)) & 3
-		binary.LittleEndian.PutUint32(dst[offset:], uint32(l))
-		offset += 4
-		copy(dst[offset:], text)
-		offset += l + pad
-	case VariableTypeNull, VariableTypeNoSuchObject, VariableTypeNoSuchInstance, VariableTypeEndOfMIBView:
-		// no payload
-	case VariableTypeObjectIdentifier:
-		// Accept string or value.OID
-		var oidVal value.OID
-		switch vv := v.Value.(type) {
+		switch octets := v.Value.(type) {
 		case string:
-			parsed, err := value.ParseOID(vv)
-			if err != nil {
-				return 0, err
-			}
-			oidVal = parsed
-		case value.OID:
-			oidVal = vv
+			return value.OctetString(octets).AppendBinary(b)
+		case []byte:
+			return value.OctetString(octets).AppendBinary(b)
 		default:
-			return 0, fmt.Errorf("unexpected OID value type %T", v.Value)
+			return nil, v.invalidValueError()
 		}
-		// Apply AgentX prefix compression like ObjectIdentifier.SetIdentifier
-		var subids []uint32
-		prefix := byte(0)
-		if len(oidVal) > 4 && oidVal[0] == 1 && oidVal[1] == 3 && oidVal[2] == 6 && oidVal[3] == 1 {
-			prefix = byte(oidVal[4])
-			subids = oidVal[5:]
-		} else {
-			subids = oidVal
+	case VariableTypeObjectIdentifier:
+		text, ok := v.Value.(string)
+		if !ok {
+			return nil, v.invalidValueError()
 		}
-		count := len(subids)
-		dst[offset] = byte(count)
-		dst[offset+1] = prefix
-		dst[offset+2] = 0 // Include defaults to false for value OID
-		offset += 4
-		for i, sub := range subids {
-			binary.LittleEndian.PutUint32(dst[offset+i*4:], sub)
+		oid, err := value.ParseOID(text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidValue, err)
 		}
-		offset += count * 4
+		return oid.AppendBinary(b)
 	case VariableTypeIPAddress:
-		ip := []byte(v.Value.(net.IP))
-		l := len(ip)
-		pad := (4 - (l % 4)) & 3
-		binary.LittleEndian.PutUint32(dst[offset:], uint32(l))
-		offset += 4
-		copy(dst[offset:], ip)
-		offset += l + pad
+		switch v.Value.(type) {
+		case net.IP, netip.Addr:
+		default:
+			return nil, v.invalidValueError()
+		}
+		ip, err := value.Convert(value.TypeIPAddress, v.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidValue, err)
+		}
+		return ip.AppendBinary(b)
 	case VariableTypeCounter32, VariableTypeGauge32:
-		value := v.Value.(uint32)
-		binary.LittleEndian.PutUint32(dst[offset:], value)
-		offset += 4
+		value, ok := v.Value.(uint32)
+		if !ok {
+			return nil, v.invalidValueError()
+		}
+		b = binary.LittleEndian.AppendUint32(b, value)
 	case VariableTypeTimeTicks:
-		value := uint32(v.Value.(time.Duration).Seconds() * 100)
-		binary.LittleEndian.PutUint32(dst[offset:], value)
-		offset += 4
+		value, ok := v.Value.(time.Duration)
+		if !ok {
+			return nil, v.invalidValueError()
+		}
+		b = binary.LittleEndian.AppendUint32(b, uint32(value.Seconds()*100))
 	case VariableTypeOpaque:
-		data := v.Value.([]byte)
-		l := len(data)
-		pad := (4 - (l % 4)) & 3
-		binary.LittleEndian.PutUint32(dst[offset:], uint32(l))
-		offset += 4
-		copy(dst[offset:], data)
-		offset += l + pad
+		data, ok := v.Value.([]byte)
+		if !ok {
+			return nil, v.invalidValueError()
+		}
+		b = wire.AppendOctets(b, data)
 	case VariableTypeCounter64:
-		value := v.Value.(uint64)
-		binary.LittleEndian.PutUint64(dst[offset:], value)
-		offset += 8
+		value, ok := v.Value.(uint64)
+		if !ok {
+			return nil, v.invalidValueError()
+		}
+		b = binary.LittleEndian.AppendUint64(b, value)
 	default:
-		return 0, fmt.Errorf("unhandled variable type %s", v.Type)
+		return nil, fmt.Errorf("unhandled variable type %s", v.Type)
+	}
+
+	return b, nil
+}
+
+// Bytes returns the octets of an octet string, opaque or ip address variable.
+// The second return value is false for all other variables.
+func (v *Variable) Bytes() ([]byte, bool) {
+	switch octets := v.Value.(type) {
+	case string:
+		if v.Type == VariableTypeOctetString {
+			return []byte(octets), true
+		}
+	case []byte:
+		if v.Type == VariableTypeOctetString || v.Type == VariableTypeOpaque {
+			return octets, true
+		}
+	case net.IP:
+		if v.Type == VariableTypeIPAddress {
+			if ip4 := octets.To4(); ip4 != nil {
+				return ip4, true
+			}
+			return octets, true
+		}
+	case value.OctetString:
+		return octets, true
+	case value.DisplayString:
+		return []byte(octets), true
+	case value.Opaque:
+		return octets, true
+	case value.IPAddress:
+		return octets[:], true
 	}
+	return nil, false
+}
 
-	return offset, nil
+func (v *Variable) invalidValueError() error {
+	return fmt.Errorf("%w %T for %s", ErrInvalidValue, v.Value, v.Type)
 }
 
 // UnmarshalBinary sets the packet structure from the provided slice of bytes.
 func (v *Variable) UnmarshalBinary(data []byte) error {
 	// Type + 3 reserved bytes
+	if err := checkSize(data, 4, "variable header"); err != nil {
+		return err
+	}
 	v.Type = VariableType(data[0])
 	offset := 4
 
 	if err := v.Name.UnmarshalBinary(data[offset:]); err != nil {
-		return err
+		return fmt.Errorf("variable name: %w", err)
 	}
 	offset += v.Name.ByteSize()
 
 	switch v.Type {
 	case VariableTypeInteger:
+		if err := checkSize(data[offset:], 4, "integer"); err != nil {
+			return err
+		}
 		v.Value = int32(binary.LittleEndian.Uint32(data[offset:]))
-		offset += 4
 	case VariableTypeOctetString:
-		length := int(binary.LittleEndian.Uint32(data[offset:]))
-		start := offset + 4
-		end := start + length
-		v.Value = string(data[start:end])
+		octets, err := unmarshalOctets(data[offset:], "octet string")
+		if err != nil {
+			return err
+		}
+		v.Value = string(octets)
 	case VariableTypeNull, VariableTypeNoSuchObject, VariableTypeNoSuchInstance, VariableTypeEndOfMIBView:
 		v.Value = nil
 	case VariableTypeObjectIdentifier:
 		oid := &ObjectIdentifier{}
 		if err := oid.UnmarshalBinary(data[offset:]); err != nil {
-			return err
+			return fmt.Errorf("variable value: %w", err)
 		}
 		v.Value = oid.GetIdentifier()
 	case VariableTypeIPAddress:
-		length := int(binary.LittleEndian.Uint32(data[offset:]))
-		start := offset + 4
-		end := start + length
-		b := make([]byte, length)
-		copy(b, data[start:end])
+		octets, err := unmarshalOctets(data[offset:], "ip address")
+		if err != nil {
+			return err
+		}
+		b := make([]byte, len(octets))
+		copy(b, octets)
 		v.Value = net.IP(b)
 	case VariableTypeCounter32, VariableTypeGauge32:
+		if err := checkSize(data[offset:], 4, "counter"); err != nil {
+			return err
+		}
 		v.Value = binary.LittleEndian.Uint32(data[offset:])
-		offset += 4
 	case VariableTypeTimeTicks:
+		if err := checkSize(data[offset:], 4, "time ticks"); err != nil {
+			return err
+		}
 		value := binary.LittleEndian.Uint32(data[offset:])
-		offset += 4
 		v.Value = time.Duration(value) * time.Second / 100
 	case VariableTypeOpaque:
-		length := int(binary.LittleEndian.Uint32(data[offset:]))
-		start := offset + 4
-		end := start + length
-		b := make([]byte, length)
-		copy(b, data[start:end])
+		octets, err := unmarshalOctets(data[offset:], "opaque")
+		if err != nil {
+			return err
+		}
+		b := make([]byte, len(octets))
+		copy(b, octets)
 		v.Value = b
 	case VariableTypeCounter64:
+		if err := checkSize(data[offset:], 8, "counter64"); err != nil {
+			return err
+		}
 		v.Value = binary.LittleEndian.Uint64(data[offset:])
-		offset += 8
 	default:
 		return fmt.Errorf("unhandled variable type %s", v.Type)
 	}
@@ -257,6 +302,19 @@ func (v *Variable) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// unmarshalOctets returns the octets of the length-prefixed and padded
+// octet sequence at the start of data. The result references data.
+func unmarshalOctets(data []byte, name string) ([]byte, error) {
+	if err := checkSize(data, 4, name+" length"); err != nil {
+		return nil, err
+	}
+	length := binary.LittleEndian.Uint32(data)
+	if uint64(length) > uint64(len(data)-4) {
+		return nil, fmt.Errorf("%w: %s announces %d bytes, got %d", ErrShortPacket, name, length, len(data)-4)
+	}
+	return data[4 : 4+length], nil
+}
+
 func (v *Variable) String() string {
 	return fmt.Sprintf("(variable %s = %v)", v.Type, v.Value)
 }
@@ -0,0 +1,167 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package pdu_test
+
+import (
+	"io"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Olian04/go-agentx/pdu"
+	"github.com/Olian04/go-agentx/value"
+)
+
+func TestVariableTypedValue(t *testing.T) {
+	variables := pdu.Variables{}
+	variables.Add(value.MustParseOID("1.3.6.1.4.1.45995.3.1"), 0, value.Integer32(-123))
+	variables.Add(value.MustParseOID("1.3.6.1.4.1.45995.3.2"), 0, value.OctetString("test"))
+	variables.Add(value.MustParseOID("1.3.6.1.4.1.45995.3.3"), 0, value.MustParseOID("1.3.6.1.4.1.45995.1.5"))
+	assert.Equal(t, pdu.VariableTypeInteger, variables[0].Type)
+	assert.Equal(t, pdu.VariableTypeOctetString, variables[1].Type)
+	assert.Equal(t, pdu.VariableTypeObjectIdentifier, variables[2].Type)
+
+	data, err := variables.MarshalBinary()
+	require.NoError(t, err)
+	assert.Len(t, data, variables.ByteSize())
+
+	decoded := pdu.Variables{}
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, int32(-123), decoded[0].Value)
+	assert.Equal(t, "test", decoded[1].Value)
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.1.5"), decoded[2].Value)
+}
+
+func TestVariableMarshalTo(t *testing.T) {
+	variable := pdu.Variable{}
+	variable.Set(value.MustParseOID("1.3.6.1.4.1.45995.3.1"), 0, value.OctetString("test"))
+	expected, err := variable.MarshalBinary()
+	require.NoError(t, err)
+
+	dst := make([]byte, variable.ByteSize())
+	n, err := variable.MarshalTo(dst)
+	require.NoError(t, err)
+	assert.Equal(t, expected, dst[:n])
+
+	n, err = variable.MarshalTo(make([]byte, variable.ByteSize()-1))
+	assert.ErrorIs(t, err, io.ErrShortBuffer)
+	assert.Zero(t, n)
+}
+
+func TestVariableOIDPrefixZero(t *testing.T) {
+	oid := value.MustParseOID("1.3.6.1.0.5")
+	variables := pdu.Variables{}
+	variables.Add(oid, 0, oid)
+
+	data, err := variables.MarshalBinary()
+	require.NoError(t, err)
+	assert.Len(t, data, variables.ByteSize())
+
+	decoded := pdu.Variables{}
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, oid, decoded[0].Name.GetIdentifier())
+	assert.Equal(t, oid, decoded[0].Value)
+}
+
+func TestVariableOIDTooLong(t *testing.T) {
+	variable := pdu.Variable{}
+	variable.Set(make(value.OID, 256), pdu.VariableTypeNull, nil)
+	_, err := variable.MarshalBinary()
+	assert.Error(t, err)
+
+	variable.Set(value.MustParseOID("1.3.6.1.4.1.45995.3.1"), 0, make(value.OID, 256))
+	_, err = variable.MarshalBinary()
+	assert.ErrorIs(t, err, value.ErrInvalidOID)
+}
+
+func TestVariableInvalidValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		typ   pdu.VariableType
+		value any
+	}{
+		{"int for integer", pdu.VariableTypeInteger, 5},
+		{"int for counter32", pdu.VariableTypeCounter32, 5},
+		{"nil for octet string", pdu.VariableTypeOctetString, nil},
+		{"typed value of another type", pdu.VariableTypeGauge32, value.Counter32(5)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			variable := pdu.Variable{}
+			variable.Set(value.MustParseOID("1.3.6.1.4.1.45995.3.1"), test.typ, test.value)
+
+			assert.NotPanics(t, func() {
+				_, err := variable.MarshalBinary()
+				assert.ErrorIs(t, err, pdu.ErrInvalidValue)
+			})
+		})
+	}
+}
+
+func TestVariableOctetStringBytes(t *testing.T) {
+	mac := []byte{0x00, 0x1b, 0x21, 0xff, 0x00, 0x80}
+
+	variables := pdu.Variables{}
+	variables.Add(value.MustParseOID("1.3.6.1.2.1.2.2.1.6.1"), pdu.VariableTypeOctetString, mac)
+	data, err := variables.MarshalBinary()
+	require.NoError(t, err)
+	assert.Len(t, data, variables.ByteSize())
+
+	decoded := pdu.Variables{}
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	octets, ok := decoded[0].Bytes()
+	require.True(t, ok)
+	assert.Equal(t, mac, octets)
+
+	_, ok = (&pdu.Variable{Type: pdu.VariableTypeInteger, Value: int32(1)}).Bytes()
+	assert.False(t, ok)
+}
+
+func TestVariableOctetStringTooLong(t *testing.T) {
+	variable := pdu.Variable{}
+	variable.Set(value.MustParseOID("1.3.6.1.4.1.45995.3.1"), pdu.VariableTypeOctetString, make([]byte, value.MaxOctetStringLength+1))
+
+	_, err := variable.MarshalBinary()
+	assert.ErrorIs(t, err, value.ErrTooLong)
+}
+
+func TestVariableIPAddress(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{"4-byte net.IP", net.IP{192, 168, 1, 1}},
+		{"16-byte net.IP", net.ParseIP("192.168.1.1")},
+		{"netip.Addr", netip.MustParseAddr("192.168.1.1")},
+		{"IPv4-mapped netip.Addr", netip.MustParseAddr("::ffff:192.168.1.1")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			variables := pdu.Variables{}
+			variables.Add(value.MustParseOID("1.3.6.1.2.1.4.20.1.1"), pdu.VariableTypeIPAddress, test.value)
+			data, err := variables.MarshalBinary()
+			require.NoError(t, err)
+			assert.Len(t, data, variables.ByteSize())
+
+			decoded := pdu.Variables{}
+			require.NoError(t, decoded.UnmarshalBinary(data))
+			assert.Equal(t, net.IP{192, 168, 1, 1}, decoded[0].Value)
+		})
+	}
+}
+
+func TestVariableIPAddressIPv6(t *testing.T) {
+	for _, ip := range []any{net.ParseIP("2001:db8::1"), netip.MustParseAddr("2001:db8::1")} {
+		variable := pdu.Variable{}
+		variable.Set(value.MustParseOID("1.3.6.1.2.1.4.20.1.1"), pdu.VariableTypeIPAddress, ip)
+
+		_, err := variable.MarshalBinary()
+		assert.ErrorIs(t, err, pdu.ErrInvalidValue)
+		assert.ErrorIs(t, err, value.ErrConversion)
+	}
+}
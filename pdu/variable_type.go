@@ -57,3 +57,12 @@ func (v VariableType) String() string {
 	}
 	return fmt.Sprintf("VariableTypeUnknown (%d)", v)
 }
+
+// isException returns true for the exception types, that don't carry a value.
+func (v VariableType) isException() bool {
+	switch v {
+	case VariableTypeNull, VariableTypeNoSuchObject, VariableTypeNoSuchInstance, VariableTypeEndOfMIBView:
+		return true
+	}
+	return false
+}
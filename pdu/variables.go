@@ -6,6 +6,7 @@ package pdu
 
 import (
 	"encoding/binary"
+	"fmt"
 	"strings"
 
 	"github.com/Olian04/go-agentx/value"
@@ -24,20 +25,27 @@ func (v *Variables) Add(oid value.OID, t VariableType, value interface{}) {
 // MarshalBinary returns the pdu packet as a slice of bytes.
 func (v *Variables) MarshalBinary() ([]byte, error) {
 	// Precompute total size to allocate once
-	total := 0
-	for i := range *v {
-		total += (*v)[i].ByteSize()
-	}
-	result := make([]byte, total)
-	offset := 0
+	return v.AppendBinary(make([]byte, 0, v.ByteSize()))
+}
+
+// AppendBinary appends the encoded variables to b and returns the extended slice.
+func (v *Variables) AppendBinary(b []byte) ([]byte, error) {
 	for i := range *v {
-		n, err := (*v)[i].MarshalTo(result[offset:])
-		if err != nil {
+		var err error
+		if b, err = (*v)[i].AppendBinary(b); err != nil {
 			return nil, err
 		}
-		offset += n
 	}
-	return result, nil
+	return b, nil
+}
+
+// ByteSize returns the number of bytes, the variables would need in the encoded version.
+func (v *Variables) ByteSize() int {
+	size := 0
+	for i := range *v {
+		size += (*v)[i].ByteSize()
+	}
+	return size
 }
 
 // UnmarshalBinary sets the packet structure from the provided slice of bytes.
@@ -47,7 +55,7 @@ func (v *Variables) UnmarshalBinary(data []byte) error {
 	for off := 0; off < len(data); {
 		size, err := encodedVarSize(data[off:])
 		if err != nil {
-			return err
+			return fmt.Errorf("variable %d: %w", count+1, err)
 		}
 		off += size
 		count++
@@ -55,12 +63,16 @@ func (v *Variables) UnmarshalBinary(data []byte) error {
 
 	*v = make([]Variable, 0, count)
 	for offset := 0; offset < len(data); {
+		size, err := encodedVarSize(data[offset:])
+		if err != nil {
+			return fmt.Errorf("variable %d: %w", len(*v)+1, err)
+		}
 		variable := Variable{}
-		if err := variable.UnmarshalBinary(data[offset:]); err != nil {
-			return err
+		if err := variable.UnmarshalBinary(data[offset : offset+size]); err != nil {
+			return fmt.Errorf("variable %d: %w", len(*v)+1, err)
 		}
 		*v = append(*v, variable)
-		offset += variable.ByteSize()
+		offset += size
 	}
 	return nil
 }
@@ -75,43 +87,47 @@ func (v Variables) String() string {
 
 // encodedVarSize returns the number of bytes occupied by a single encoded Variable at data.
 func encodedVarSize(data []byte) (int, error) {
-	if len(data) < 8 {
-		return 0, nil
-	}
 	// header
+	if err := checkSize(data, 4, "variable header"); err != nil {
+		return 0, err
+	}
 	offset := 4
 	// name ObjectIdentifier
-	if len(data) < offset+1 {
-		return 0, nil
+	nameSize, err := encodedOIDSize(data[offset:])
+	if err != nil {
+		return 0, err
 	}
-	nameCount := int(data[offset])
-	nameSize := 4 + nameCount*4
 	offset += nameSize
-	if len(data) < offset {
-		return 0, nil
-	}
+
+	size := offset
 	t := VariableType(data[0])
 	switch t {
 	case VariableTypeInteger, VariableTypeCounter32, VariableTypeGauge32, VariableTypeTimeTicks:
-		return offset + 4, nil
+		size += 4
 	case VariableTypeCounter64:
-		return offset + 8, nil
+		size += 8
 	case VariableTypeOctetString, VariableTypeIPAddress, VariableTypeOpaque:
-		if len(data) < offset+4 {
-			return 0, nil
+		if err := checkSize(data[offset:], 4, "octet string length"); err != nil {
+			return 0, err
 		}
 		l := int(binary.LittleEndian.Uint32(data[offset:]))
+		if l < 0 || l > len(data) {
+			return 0, fmt.Errorf("%w: octet string announces %d bytes, got %d", ErrShortPacket, l, len(data)-offset-4)
+		}
 		pad := (4 - (l % 4)) & 3
-		return offset + 4 + l + pad, nil
+		size += 4 + l + pad
 	case VariableTypeObjectIdentifier:
-		if len(data) < offset+1 {
-			return 0, nil
+		valSize, err := encodedOIDSize(data[offset:])
+		if err != nil {
+			return 0, err
 		}
-		valCount := int(data[offset])
-		return offset + 4 + valCount*4, nil
+		size += valSize
 	case VariableTypeNull, VariableTypeNoSuchObject, VariableTypeNoSuchInstance, VariableTypeEndOfMIBView:
-		return offset, nil
 	default:
-		return offset, nil
+		return 0, fmt.Errorf("unhandled variable type %s", t)
+	}
+	if err := checkSize(data, size, "variable"); err != nil {
+		return 0, err
 	}
+	return size, nil
 }
@@ -2,7 +2,7 @@
 // Licensed under the LGPLv3 with static-linking exception.
 // See LICENCE file for details.
 //
-// Pooling utilities for buffers and request structs.
+// Pooling utilities for request structs.
 
 package agentx
 
@@ -12,18 +12,7 @@ import (
 	"github.com/Olian04/go-agentx/pdu"
 )
 
-const pooledBufCap = 8 << 10 // 8KB default pooled buffer size
-
 var (
-	headerBufPool = sync.Pool{
-		New: func() any {
-			var b [pdu.HeaderSize]byte
-			return &b
-		},
-	}
-	ioBufPool = sync.Pool{
-		New: func() any { return &pooledBytes{b: make([]byte, pooledBufCap)} },
-	}
 	requestPool = sync.Pool{
 		New: func() any { return &request{} },
 	}
@@ -35,33 +24,6 @@ var (
 	}
 )
 
-type pooledBytes struct {
-	b []byte
-}
-
-func acquireHeaderBuf() *[pdu.HeaderSize]byte {
-	return headerBufPool.Get().(*[pdu.HeaderSize]byte)
-}
-
-func releaseHeaderBuf(b *[pdu.HeaderSize]byte) {
-	headerBufPool.Put(b)
-}
-
-func acquireIOBuf(n int) (*pooledBytes, []byte) {
-	p := ioBufPool.Get().(*pooledBytes)
-	if cap(p.b) < n {
-		p.b = make([]byte, n)
-	}
-	return p, p.b[:n]
-}
-
-func releaseIOBuf(p *pooledBytes) {
-	// Avoid retaining extremely large buffers in the pool
-	if cap(p.b) <= 64<<10 {
-		ioBufPool.Put(p)
-	}
-}
-
 func acquireRequest() *request {
 	return requestPool.Get().(*request)
 }
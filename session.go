@@ -9,6 +9,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/Olian04/go-agentx/pdu"
@@ -22,8 +24,19 @@ type Session struct {
 	sessionID uint32
 	timeout   time.Duration
 
-	openRequestPacket     *pdu.HeaderPacket
-	registerRequestPacket *pdu.HeaderPacket
+	openRequestPacket *pdu.HeaderPacket
+
+	mu sync.Mutex
+	// registrations contains the register requests of all registered subtrees.
+	registrations []*pdu.HeaderPacket
+	// inFlight contains the subtrees, whose registration is currently changed by
+	// Register or Unregister.
+	inFlight map[registrationKey]struct{}
+}
+
+type registrationKey struct {
+	priority byte
+	subtree  string
 }
 
 func openSession(client *Client, nameOID value.OID, name string, handler Handler) (*Session, error) {
@@ -55,10 +68,11 @@ func (s *Session) ID() uint32 {
 }
 
 // Register registers the client under the provided rootID with the provided priority
-// on the master agent.
+// on the master agent. A session can register multiple subtrees.
 func (s *Session) Register(priority byte, baseOID value.OID) error {
-	if s.registerRequestPacket != nil {
-		return fmt.Errorf("session is already registered")
+	key, err := s.beginRegistration(priority, baseOID, true)
+	if err != nil {
+		return err
 	}
 
 	requestPacket := &pdu.Register{}
@@ -68,17 +82,22 @@ func (s *Session) Register(priority byte, baseOID value.OID) error {
 	request := &pdu.HeaderPacket{Header: &pdu.Header{Type: pdu.TypeRegister}, Packet: requestPacket}
 
 	response := s.request(request)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.inFlight, key)
 	if err := checkError(response); err != nil {
 		return err
 	}
-	s.registerRequestPacket = request
+	s.registrations = append(s.registrations, request)
 	return nil
 }
 
 // Unregister removes the registration for the provided subtree.
 func (s *Session) Unregister(priority byte, baseOID value.OID) error {
-	if s.registerRequestPacket == nil {
-		return fmt.Errorf("session is not registered")
+	key, err := s.beginRegistration(priority, baseOID, false)
+	if err != nil {
+		return err
 	}
 
 	requestPacket := &pdu.Unregister{}
@@ -88,13 +107,52 @@ func (s *Session) Unregister(priority byte, baseOID value.OID) error {
 	request := &pdu.HeaderPacket{Header: &pdu.Header{}, Packet: requestPacket}
 
 	response := s.request(request)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.inFlight, key)
 	if err := checkError(response); err != nil {
 		return err
 	}
-	s.registerRequestPacket = nil
+	if i := s.registrationIndex(priority, baseOID); i >= 0 {
+		s.registrations = slices.Delete(s.registrations, i, i+1)
+	}
 	return nil
 }
 
+// beginRegistration checks, that the subtree is (or isn't, if register is set)
+// registered, and marks it as in flight until the response of the master agent
+// is processed. Concurrent calls for the same subtree fail, so a subtree can't
+// be registered twice.
+func (s *Session) beginRegistration(priority byte, baseOID value.OID, register bool) (registrationKey, error) {
+	key := registrationKey{priority: priority, subtree: baseOID.String()}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.inFlight[key]; ok {
+		return key, fmt.Errorf("registration for %s is in progress", baseOID)
+	}
+	registered := s.registrationIndex(priority, baseOID) >= 0
+	if register && registered {
+		return key, fmt.Errorf("session is already registered for %s", baseOID)
+	}
+	if !register && !registered {
+		return key, fmt.Errorf("session is not registered for %s", baseOID)
+	}
+	if s.inFlight == nil {
+		s.inFlight = make(map[registrationKey]struct{})
+	}
+	s.inFlight[key] = struct{}{}
+	return key, nil
+}
+
+func (s *Session) registrationIndex(priority byte, baseOID value.OID) int {
+	return slices.IndexFunc(s.registrations, func(hp *pdu.HeaderPacket) bool {
+		register := hp.Packet.(*pdu.Register)
+		return register.Timeout.Priority == priority && register.Subtree.GetIdentifier().Equal(baseOID)
+	})
+}
+
 // Close tears down the session with the master agent.
 func (s *Session) Close() error {
 	requestPacket := &pdu.Close{Reason: pdu.ReasonShutdown}
@@ -115,8 +173,11 @@ func (s *Session) reopen() error {
 		s.sessionID = response.Header.SessionID
 	}
 
-	if s.registerRequestPacket != nil {
-		response := s.request(s.registerRequestPacket)
+	s.mu.Lock()
+	registrations := slices.Clone(s.registrations)
+	s.mu.Unlock()
+	for _, request := range registrations {
+		response := s.request(request)
 		if err := checkError(response); err != nil {
 			return err
 		}
@@ -131,13 +192,6 @@ func (s *Session) request(hp *pdu.HeaderPacket) *pdu.HeaderPacket {
 }
 
 func (s *Session) handle(request *pdu.HeaderPacket) *pdu.HeaderPacket {
-	responseHeader := acquireHeader()
-	responseHeader.SessionID = request.Header.SessionID
-	responseHeader.TransactionID = request.Header.TransactionID
-	responseHeader.PacketID = request.Header.PacketID
-	// We always encode using little-endian (FlagNetworkByteOrder unset)
-	// Ensure the response header Flags reflect our encoding.
-	responseHeader.Flags = 0
 	responsePacket := &pdu.Response{}
 
 	ctx := context.Background()
@@ -159,18 +213,24 @@ func (s *Session) handle(request *pdu.HeaderPacket) *pdu.HeaderPacket {
 
 		// One response varbind per requested OID
 		responsePacket.Variables = make(pdu.Variables, 0, len(requestPacket.SearchRanges))
-		for _, sr := range requestPacket.SearchRanges {
+		for i, sr := range requestPacket.SearchRanges {
 			reqOID := sr.From.GetIdentifier()
-			oid, t, v, err := s.handler.Get(ctx, reqOID)
-			if err != nil {
-				s.client.logger.Error("packet error", slog.Any("err", err))
-				responsePacket.Error = pdu.ErrorProcessing
-			}
-			if oid == nil {
-				responsePacket.Variables.Add(reqOID, pdu.VariableTypeNoSuchObject, nil)
-			} else {
-				responsePacket.Variables.Add(oid, t, v)
-			}
+			s.handleVariable(request.Header, responsePacket, i, reqOID, func() {
+				oid, t, v, err := s.handler.Get(ctx, reqOID)
+				if err != nil {
+					s.client.logger.Error("packet error", slog.Any("err", err))
+					setError(responsePacket, i, err)
+				}
+				if oid == nil {
+					// the handler can tell, that the object exists, but not the instance
+					if t != pdu.VariableTypeNoSuchInstance {
+						t = pdu.VariableTypeNoSuchObject
+					}
+					responsePacket.Variables.Add(reqOID, t, nil)
+				} else {
+					s.addVariable(responsePacket, i, oid, t, v)
+				}
+			})
 		}
 
 	case *pdu.GetNext:
@@ -180,18 +240,20 @@ func (s *Session) handle(request *pdu.HeaderPacket) *pdu.HeaderPacket {
 		}
 
 		responsePacket.Variables = make(pdu.Variables, 0, len(requestPacket.SearchRanges))
-		for _, sr := range requestPacket.SearchRanges {
-			oid, t, v, err := s.handler.GetNext(ctx, sr.From.GetIdentifier(), (sr.From.Include == 1), sr.To.GetIdentifier())
-			if err != nil {
-				s.client.logger.Error("packet error", slog.Any("err", err))
-				responsePacket.Error = pdu.ErrorProcessing
-			}
-
-			if oid == nil {
-				responsePacket.Variables.Add(sr.From.GetIdentifier(), pdu.VariableTypeEndOfMIBView, nil)
-			} else {
-				responsePacket.Variables.Add(oid, t, v)
-			}
+		for i, sr := range requestPacket.SearchRanges {
+			s.handleVariable(request.Header, responsePacket, i, sr.From.GetIdentifier(), func() {
+				oid, t, v, err := s.handler.GetNext(ctx, sr.From.GetIdentifier(), (sr.From.Include == 1), sr.To.GetIdentifier())
+				if err != nil {
+					s.client.logger.Error("packet error", slog.Any("err", err))
+					setError(responsePacket, i, err)
+				}
+
+				if oid == nil {
+					responsePacket.Variables.Add(sr.From.GetIdentifier(), pdu.VariableTypeEndOfMIBView, nil)
+				} else {
+					s.addVariable(responsePacket, i, oid, t, v)
+				}
+			})
 		}
 
 	default:
@@ -199,12 +261,132 @@ func (s *Session) handle(request *pdu.HeaderPacket) *pdu.HeaderPacket {
 		responsePacket.Error = pdu.ErrorProcessing
 	}
 
+	_, truncate := request.Packet.(*pdu.GetNext)
+	s.fitResponse(request.Header, responsePacket, truncate)
+
+	return newResponse(request.Header, responsePacket)
+}
+
+// fitResponse ensures, that the response doesn't exceed the maximum payload
+// length. If truncate is set, trailing variables are dropped until it fits.
+// Responses, that don't fit otherwise, are replaced by a tooBig error.
+func (s *Session) fitResponse(header *pdu.Header, response *pdu.Response, truncate bool) {
+	limit := int(s.client.options.maxPayloadLength)
+	size := response.ByteSize()
+	if size <= limit {
+		return
+	}
+
+	if truncate {
+		n := len(response.Variables)
+		for n > 1 && size > limit {
+			n--
+			size -= response.Variables[n].ByteSize()
+		}
+		if size <= limit {
+			s.client.logger.Warn("response truncated",
+				getPacketHeaderSlogAttrs(header),
+				slog.Int("variables", n),
+				slog.Int("dropped_variables", len(response.Variables)-n),
+			)
+			response.Variables = response.Variables[:n]
+			if int(response.Index) > n {
+				// the failing variable has been dropped
+				response.Error = pdu.ErrorNone
+				response.Index = 0
+			}
+			return
+		}
+	}
+
+	s.client.logger.Error("response too big",
+		getPacketHeaderSlogAttrs(header),
+		slog.Int("size", response.ByteSize()),
+	)
+	response.Error = pdu.ErrorTooBig
+	response.Index = 0
+	response.Variables = nil
+}
+
+// handleVariable calls fn, that adds the variable for the i-th search range of
+// the request to the response. A panic is reported and answered with a
+// processing error, that points to the variable. This is the only place, where
+// panics of handlers and values are recovered, apart from the encoding of the
+// response (see Client.encode).
+func (s *Session) handleVariable(header *pdu.Header, response *pdu.Response, i int, oid value.OID, fn func()) {
+	n := len(response.Variables)
+	defer func() {
+		if r := recover(); r != nil {
+			s.client.reportPanic(header, r)
+			response.Variables = response.Variables[:n]
+			response.Variables.Add(oid, pdu.VariableTypeNull, nil)
+			setError(response, i, pdu.ErrorProcessing)
+		}
+	}()
+	fn()
+}
+
+// setError sets the error status of the response to the pdu.Error in the chain
+// of the provided error or to processingError, if there is none. The index
+// points to the i-th variable. Only the first failing variable is reported.
+func setError(response *pdu.Response, i int, err error) {
+	if response.Error != pdu.ErrorNone {
+		return
+	}
+	status := pdu.ErrorProcessing
+	if pduErr := pdu.Error(0); errors.As(err, &pduErr) && pduErr != pdu.ErrorNone {
+		status = pduErr
+	}
+	response.Error = status
+	response.Index = uint16(i + 1)
+}
+
+// newResponse returns the header packet of a response to the request with the
+// provided header.
+func newResponse(request *pdu.Header, response *pdu.Response) *pdu.HeaderPacket {
+	header := acquireHeader()
+	header.SessionID = request.SessionID
+	header.TransactionID = request.TransactionID
+	header.PacketID = request.PacketID
+	// We always encode using little-endian (FlagNetworkByteOrder unset)
+	// Ensure the response header Flags reflect our encoding.
+	header.Flags = 0
+
 	hp := acquireHeaderPacket()
-	hp.Header = responseHeader
-	hp.Packet = responsePacket
+	hp.Header = header
+	hp.Packet = response
 	return hp
 }
 
+// addVariable adds a variable with the value returned by the handler to the response.
+// The value is converted into a typed value of the provided type. If that fails,
+// a null variable is added and the response reports a genErr for the i-th variable.
+func (s *Session) addVariable(response *pdu.Response, i int, oid value.OID, t pdu.VariableType, v any) {
+	if typed, ok := v.(value.Value); ok && t == 0 {
+		t = pdu.VariableType(typed.Type())
+	}
+	switch t {
+	case pdu.VariableTypeNull, pdu.VariableTypeNoSuchObject, pdu.VariableTypeNoSuchInstance, pdu.VariableTypeEndOfMIBView:
+		response.Variables.Add(oid, t, nil)
+		return
+	}
+
+	converted, err := value.Convert(value.Type(t), v)
+	if err != nil {
+		s.client.logger.Error("value error",
+			slog.String("oid", oid.NamedString()),
+			slog.Any("err", err),
+		)
+		setError(response, i, pdu.ErrorGenErr)
+		response.Variables.Add(oid, pdu.VariableTypeNull, nil)
+		return
+	}
+	// The size is needed to fit the response into the payload limit. Taking it
+	// here lets a faulty value panic while its variable is handled.
+	_ = converted.ByteSize()
+	response.Variables.Add(oid, t, converted)
+}
+
 func checkError(hp *pdu.HeaderPacket) error {
 	response, ok := hp.Packet.(*pdu.Response)
 	if !ok {
@@ -213,5 +395,5 @@ func checkError(hp *pdu.HeaderPacket) error {
 	if response.Error == pdu.ErrorNone {
 		return nil
 	}
-	return errors.New(response.Error.String())
+	return response.Error
 }
@@ -0,0 +1,188 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package agentx
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Olian04/go-agentx/pdu"
+	"github.com/Olian04/go-agentx/value"
+)
+
+// testSession returns a session, that serves the provided handler without a
+// connection.
+func testSession(handler Handler) *Session {
+	client := &Client{
+		logger:  slog.New(slog.DiscardHandler),
+		options: dialOptions{maxPayloadLength: pdu.MinMaxPayloadLength},
+	}
+	return &Session{client: client, handler: handler, sessionID: 1}
+}
+
+// testRequest returns a request with a search range for each oid, that ends
+// with the test subtree.
+func testRequest(packet pdu.Packet, oids ...value.OID) *pdu.HeaderPacket {
+	var ranges pdu.Ranges
+	for _, oid := range oids {
+		var r pdu.Range
+		r.From.SetIdentifier(oid)
+		r.To.SetIdentifier(value.MustParseOID("1.3.6.1.4.1.45996"))
+		ranges = append(ranges, r)
+	}
+	switch packet := packet.(type) {
+	case *pdu.Get:
+		packet.SearchRanges = ranges
+	case *pdu.GetNext:
+		packet.SearchRanges = ranges
+	}
+	return &pdu.HeaderPacket{Header: &pdu.Header{SessionID: 1, Type: packet.Type()}, Packet: packet}
+}
+
+func TestSessionFitResponse(t *testing.T) {
+	base := value.MustParseOID("1.3.6.1.4.1.45995.3")
+	lh := &ListHandler{}
+	var oids []value.OID
+	for i := uint32(1); i <= 20; i++ {
+		lh.Set(base.Append(i), 0, value.OctetString(strings.Repeat("x", 100)))
+		oids = append(oids, base.Append(i))
+	}
+	lh.Set(base.Append(100), 0, value.OctetString(strings.Repeat("x", 2000)))
+	s := testSession(lh)
+
+	t.Run("Truncated", func(t *testing.T) {
+		response := s.handle(testRequest(&pdu.GetNext{}, base)).Packet.(*pdu.Response)
+		require.Len(t, response.Variables, 1)
+
+		// the ranges start before the items, so every variable has a value
+		from := make([]value.OID, len(oids))
+		for i, oid := range oids {
+			from[i] = oid.Parent().Append(oid[len(oid)-1] - 1)
+		}
+		response = s.handle(testRequest(&pdu.GetNext{}, from...)).Packet.(*pdu.Response)
+		assert.Equal(t, pdu.ErrorNone, response.Error)
+		assert.NotEmpty(t, response.Variables)
+		assert.Less(t, len(response.Variables), len(oids))
+		assert.LessOrEqual(t, response.ByteSize(), pdu.MinMaxPayloadLength)
+		for i, variable := range response.Variables {
+			assert.Equal(t, oids[i], variable.Name.GetIdentifier())
+		}
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		response := s.handle(testRequest(&pdu.Get{}, oids...)).Packet.(*pdu.Response)
+		assert.Equal(t, pdu.ErrorTooBig, response.Error)
+		assert.Empty(t, response.Variables)
+	})
+
+	t.Run("First variable", func(t *testing.T) {
+		response := s.handle(testRequest(&pdu.GetNext{}, base.Append(99))).Packet.(*pdu.Response)
+		assert.Equal(t, pdu.ErrorTooBig, response.Error)
+		assert.Empty(t, response.Variables)
+	})
+}
+
+func TestDialMaxPayloadLength(t *testing.T) {
+	_, err := Dial("tcp", "127.0.0.1:0", WithMaxPayloadLength(pdu.HeaderSize))
+	assert.ErrorContains(t, err, "below the minimum")
+}
+
+// panicValue is a value with a faulty implementation.
+type panicValue struct{}
+
+func (panicValue) Type() value.Type                      { return value.TypeOctetString }
+func (panicValue) ByteSize() int                         { panic("broken value") }
+func (panicValue) AppendBinary(b []byte) ([]byte, error) { panic("broken value") }
+
+func TestSessionPanicIndex(t *testing.T) {
+	base := value.MustParseOID("1.3.6.1.4.1.45995.3")
+	fh := &FuncHandler{}
+	fh.Add("1.3.6.1.4.1.45995.3.1", pdu.VariableTypeInteger, func(ctx context.Context) (any, error) {
+		return 1, nil
+	})
+	fh.Add("1.3.6.1.4.1.45995.3.2", pdu.VariableTypeInteger, func(ctx context.Context) (any, error) {
+		panic("broken handler")
+	})
+	fh.Add("1.3.6.1.4.1.45995.3.3", 0, func(ctx context.Context) (any, error) {
+		return panicValue{}, nil
+	})
+
+	tests := []struct {
+		name    string
+		handler Handler
+		oid     value.OID
+		panic   string
+	}{
+		{"Handler", fh, base.Append(2), "broken handler"},
+		{"Timeout", Chain(fh, Timeout(time.Second)), base.Append(2), "broken handler"},
+		{"Value", fh, base.Append(3), "broken value"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var panics []any
+			s := testSession(test.handler)
+			s.client.options.panicHandler = func(recovered any, stack []byte) {
+				panics = append(panics, recovered)
+			}
+
+			response := s.handle(testRequest(&pdu.Get{}, base.Append(1), test.oid)).Packet.(*pdu.Response)
+			assert.Equal(t, pdu.ErrorProcessing, response.Error)
+			assert.Equal(t, uint16(2), response.Index)
+			require.Len(t, response.Variables, 2)
+			assert.Equal(t, pdu.VariableTypeNull, response.Variables[1].Type)
+			assert.Equal(t, []any{test.panic}, panics)
+		})
+	}
+}
+
+func TestSessionConcurrentRegister(t *testing.T) {
+	s := testSession(nil)
+	s.client.requestChan = make(chan *request)
+	var registers atomic.Int32
+	go func() {
+		// the master agent answers slowly, so the calls overlap
+		for req := range s.client.requestChan {
+			if req.headerPacket.Packet.Type() == pdu.TypeRegister {
+				registers.Add(1)
+			}
+			time.Sleep(10 * time.Millisecond)
+			req.responseChan <- &pdu.HeaderPacket{Header: &pdu.Header{}, Packet: &pdu.Response{}}
+		}
+	}()
+	defer close(s.client.requestChan)
+
+	subtree := value.MustParseOID("1.3.6.1.4.1.45995.3")
+	var (
+		wg        sync.WaitGroup
+		succeeded atomic.Int32
+	)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if s.Register(127, subtree) == nil {
+				succeeded.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), succeeded.Load())
+	assert.Equal(t, int32(1), registers.Load())
+	assert.Len(t, s.registrations, 1)
+	assert.Error(t, s.Register(127, subtree))
+
+	require.NoError(t, s.Unregister(127, subtree))
+	assert.Empty(t, s.registrations)
+	assert.Error(t, s.Unregister(127, subtree))
+}
@@ -5,11 +5,17 @@
 package agentx_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/Olian04/go-agentx"
+	"github.com/Olian04/go-agentx/pdu"
 	"github.com/Olian04/go-agentx/value"
 )
 
@@ -44,4 +50,99 @@ func TestSession(t *testing.T) {
 		require.NoError(t,
 			session.Unregister(127, baseOID))
 	})
+
+	t.Run("RegisterMultiple", func(t *testing.T) {
+		session, err := e.client.Session(nil, "", nil)
+		require.NoError(t, err)
+		defer session.Close()
+
+		firstOID := value.MustParseOID("1.3.6.1.4.1.45995.3")
+		secondOID := value.MustParseOID("1.3.6.1.4.1.45995.4")
+
+		require.NoError(t, session.Register(127, firstOID))
+		require.NoError(t, session.Register(127, secondOID))
+		assert.Error(t, session.Register(127, firstOID))
+
+		require.NoError(t, session.Unregister(127, firstOID))
+		assert.Error(t, session.Unregister(127, firstOID))
+		require.NoError(t, session.Unregister(127, secondOID))
+	})
+}
+
+func TestSessionPanicRecovery(t *testing.T) {
+	panics := make(chan any, 1)
+	e := setUpTestEnvironment(t, agentx.WithPanicHandler(func(recovered any, stack []byte) {
+		panics <- recovered
+	}))
+
+	lh := &agentx.ListHandler{}
+	item := lh.Add("1.3.6.1.4.1.45995.3.1")
+	item.Type = pdu.VariableTypeOctetString
+	item.Value = "test"
+
+	handler := agentx.HandlerFuncs{
+		GetFunc: func(ctx context.Context, oid value.OID) (value.OID, pdu.VariableType, any, error) {
+			if oid.Equal(value.MustParseOID("1.3.6.1.4.1.45995.3.2")) {
+				panic("broken handler")
+			}
+			return lh.Get(ctx, oid)
+		},
+		GetNextFunc: lh.GetNext,
+	}
+
+	session, err := e.client.Session(value.MustParseOID("1.3.6.1.4.1.45995"), "test client", handler)
+	require.NoError(t, err)
+	defer session.Close()
+
+	baseOID := value.MustParseOID("1.3.6.1.4.1.45995")
+	require.NoError(t, session.Register(127, baseOID))
+	defer session.Unregister(127, baseOID)
+
+	output, _ := exec.Command("snmpget", "-v2c", "-cpublic", "-On", "127.0.0.1:30161",
+		"1.3.6.1.4.1.45995.3.1", "1.3.6.1.4.1.45995.3.2").CombinedOutput()
+	assert.Contains(t, string(output), "genError")
+	assert.Contains(t, string(output), "Failed object: .1.3.6.1.4.1.45995.3.2")
+	assert.Equal(t, "broken handler", <-panics)
+
+	// the client keeps serving requests
+	assert.Equal(t,
+		".1.3.6.1.4.1.45995.3.1 = STRING: \"test\"",
+		SNMPGet(t, "1.3.6.1.4.1.45995.3.1"))
+}
+
+func TestSessionHandlerErrors(t *testing.T) {
+	e := setUpTestEnvironment(t)
+
+	fh := &agentx.FuncHandler{}
+	fh.Add("1.3.6.1.4.1.45995.3.1", pdu.VariableTypeOctetString, func(context.Context) (any, error) {
+		return "test", nil
+	})
+	fh.Add("1.3.6.1.4.1.45995.3.2", pdu.VariableTypeOctetString, func(context.Context) (any, error) {
+		return nil, fmt.Errorf("access denied: %w", pdu.ErrorNoAccess)
+	})
+	fh.Add("1.3.6.1.4.1.45995.3.3", pdu.VariableTypeOctetString, func(context.Context) (any, error) {
+		return nil, errors.New("broken backend")
+	})
+
+	session, err := e.client.Session(value.MustParseOID("1.3.6.1.4.1.45995"), "test client", fh)
+	require.NoError(t, err)
+	defer session.Close()
+
+	baseOID := value.MustParseOID("1.3.6.1.4.1.45995")
+	require.NoError(t, session.Register(127, baseOID))
+	defer session.Unregister(127, baseOID)
+
+	t.Run("Typed error", func(t *testing.T) {
+		output, _ := exec.Command("snmpget", "-v2c", "-cpublic", "-On", "127.0.0.1:30161",
+			"1.3.6.1.4.1.45995.3.1", "1.3.6.1.4.1.45995.3.2").CombinedOutput()
+		assert.Contains(t, string(output), "noAccess")
+		assert.Contains(t, string(output), "Failed object: .1.3.6.1.4.1.45995.3.2")
+	})
+
+	t.Run("Untyped error", func(t *testing.T) {
+		output, _ := exec.Command("snmpget", "-v2c", "-cpublic", "-On", "127.0.0.1:30161",
+			"1.3.6.1.4.1.45995.3.3", "1.3.6.1.4.1.45995.3.1").CombinedOutput()
+		assert.Contains(t, string(output), "genError")
+		assert.Contains(t, string(output), "Failed object: .1.3.6.1.4.1.45995.3.3")
+	})
 }
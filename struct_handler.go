@@ -0,0 +1,400 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package agentx
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Olian04/go-agentx/pdu"
+	"github.com/Olian04/go-agentx/tc"
+	"github.com/Olian04/go-agentx/value"
+)
+
+// StructHandler is a helper that serves the fields of a Go struct. The fields
+// are mapped by their `snmp` tag, which has the format
+//
+//	snmp:"<subidentifier>[,<type>][,index][,implied]"
+//
+// The type is one of integer, octetstring, oid, ipaddress, counter32, gauge32,
+// timeticks, opaque and counter64. If it is omitted, it is derived from the Go
+// type of the field. Bool fields are served as TruthValue (true is 1, false
+// is 2).
+//
+// A field of any other type than a slice of structs is a scalar, that is served
+// with the oid <base>.<subidentifier>.0. A slice of structs is a table, whose
+// entries are served below <base>.<subidentifier>.1. The tagged fields of the
+// struct are the columns of the table and the fields marked with index form the
+// index of the rows (in field order). The last index field can be marked implied.
+//
+// The values are read from the struct on each request. If the struct implements
+// RLock and RUnlock (or sync.Locker), it is locked while it is read. The rows of
+// the tables are indexed and sorted on each request, unless a cache timeout is
+// set (see WithStructCacheTimeout). Rows with a nil index field are skipped.
+type StructHandler struct {
+	locker sync.Locker
+	// handlers contains the handler of the scalars, followed by the table handlers.
+	handlers []Handler
+}
+
+type structLocker struct {
+	rw interface {
+		RLock()
+		RUnlock()
+	}
+}
+
+func (l structLocker) Lock()   { l.rw.RLock() }
+func (l structLocker) Unlock() { l.rw.RUnlock() }
+
+type nopLocker struct{}
+
+func (nopLocker) Lock()   {}
+func (nopLocker) Unlock() {}
+
+// StructOption defines an option of a StructHandler.
+type StructOption func(o *structOptions)
+
+type structOptions struct {
+	cacheTimeout time.Duration
+	logger       *slog.Logger
+}
+
+// WithStructCacheTimeout sets the cache timeout of the tables (see
+// TableHandler.CacheTimeout). Changes of the rows become visible after the
+// timeout then.
+func WithStructCacheTimeout(value time.Duration) StructOption {
+	return func(o *structOptions) {
+		o.cacheTimeout = value
+	}
+}
+
+// WithStructLogger sets the logger, that reports skipped rows. Defaults to
+// slog.Default().
+func WithStructLogger(value *slog.Logger) StructOption {
+	return func(o *structOptions) {
+		o.logger = value
+	}
+}
+
+// NewStructHandler returns a handler that serves the tagged fields of the struct,
+// that v points to, below the provided base oid.
+func NewStructHandler(base value.OID, v any, opts ...StructOption) (*StructHandler, error) {
+	options := structOptions{logger: slog.Default()}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("struct handler needs a pointer to a struct, got %T", v)
+	}
+
+	scalars := &FuncHandler{}
+	h := &StructHandler{locker: nopLocker{}, handlers: []Handler{scalars}}
+	switch locker := v.(type) {
+	case interface {
+		RLock()
+		RUnlock()
+	}:
+		h.locker = structLocker{rw: locker}
+	case sync.Locker:
+		h.locker = locker
+	}
+
+	fields, err := structFields(rv.Elem().Type())
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		oid := base.Append(field.id)
+		if field.table == nil {
+			fieldValue := rv.Elem().FieldByIndex(field.index)
+			scalars.Add(oid.Append(0).String(), field.t, func(context.Context) (any, error) {
+				return fieldInterface(fieldValue), nil
+			})
+			continue
+		}
+
+		table, err := newStructTable(oid.Append(1), rv.Elem().FieldByIndex(field.index), field.table, options)
+		if err != nil {
+			return nil, fmt.Errorf("table %s: %w", oid, err)
+		}
+		h.handlers = append(h.handlers, table)
+	}
+	return h, nil
+}
+
+// Get tries to find the provided oid and returns the corresponding value.
+func (h *StructHandler) Get(ctx context.Context, oid value.OID) (value.OID, pdu.VariableType, any, error) {
+	h.locker.Lock()
+	defer h.locker.Unlock()
+
+	for _, handler := range h.handlers {
+		resultOID, t, v, err := handler.Get(ctx, oid)
+		if resultOID != nil || err != nil || t == pdu.VariableTypeNoSuchInstance {
+			return resultOID, t, v, err
+		}
+	}
+	return nil, pdu.VariableTypeNoSuchObject, nil, nil
+}
+
+// GetNext tries to find the value that follows the provided oid and returns it.
+func (h *StructHandler) GetNext(ctx context.Context, from value.OID, includeFrom bool, to value.OID) (value.OID, pdu.VariableType, any, error) {
+	h.locker.Lock()
+	defer h.locker.Unlock()
+
+	var (
+		resultOID value.OID
+		resultT   pdu.VariableType
+		resultV   any
+	)
+	for _, handler := range h.handlers {
+		oid, t, v, err := handler.GetNext(ctx, from, includeFrom, to)
+		if err != nil {
+			return oid, t, v, err
+		}
+		if oid != nil && (resultOID == nil || oid.Compare(resultOID) < 0) {
+			resultOID, resultT, resultV = oid, t, v
+		}
+	}
+	if resultOID == nil {
+		return nil, pdu.VariableTypeNoSuchObject, nil, nil
+	}
+	return resultOID, resultT, resultV, nil
+}
+
+type structField struct {
+	index   []int
+	id      uint32
+	t       pdu.VariableType
+	isIndex bool
+	implied bool
+	// table contains the columns, if the field is a slice of structs.
+	table []structField
+}
+
+// structFields returns the tagged fields of the provided struct type.
+func structFields(st reflect.Type) ([]structField, error) {
+	var fields []structField
+	for _, f := range reflect.VisibleFields(st) {
+		tag, ok := f.Tag.Lookup("snmp")
+		if !ok || !f.IsExported() {
+			continue
+		}
+		field, err := parseStructTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		field.index = f.Index
+
+		if f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() == reflect.Struct {
+			if field.table, err = structFields(f.Type.Elem()); err != nil {
+				return nil, fmt.Errorf("field %s: %w", f.Name, err)
+			}
+		} else if field.t == 0 {
+			if field.t, err = structFieldType(f.Type); err != nil {
+				return nil, fmt.Errorf("field %s: %w", f.Name, err)
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+var structTypes = map[string]pdu.VariableType{
+	"integer":     pdu.VariableTypeInteger,
+	"integer32":   pdu.VariableTypeInteger,
+	"octetstring": pdu.VariableTypeOctetString,
+	"string":      pdu.VariableTypeOctetString,
+	"oid":         pdu.VariableTypeObjectIdentifier,
+	"ipaddress":   pdu.VariableTypeIPAddress,
+	"counter32":   pdu.VariableTypeCounter32,
+	"gauge32":     pdu.VariableTypeGauge32,
+	"unsigned32":  pdu.VariableTypeGauge32,
+	"timeticks":   pdu.VariableTypeTimeTicks,
+	"opaque":      pdu.VariableTypeOpaque,
+	"counter64":   pdu.VariableTypeCounter64,
+}
+
+func parseStructTag(tag string) (structField, error) {
+	parts := strings.Split(tag, ",")
+	id, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return structField{}, fmt.Errorf("invalid subidentifier in tag %q", tag)
+	}
+
+	field := structField{id: uint32(id)}
+	for _, part := range parts[1:] {
+		switch part = strings.ToLower(strings.TrimSpace(part)); part {
+		case "index":
+			field.isIndex = true
+		case "implied":
+			field.implied = true
+		default:
+			t, ok := structTypes[part]
+			if !ok {
+				return structField{}, fmt.Errorf("unknown option %q in tag %q", part, tag)
+			}
+			field.t = t
+		}
+	}
+	return field, nil
+}
+
+var (
+	valueType    = reflect.TypeFor[value.Value]()
+	durationType = reflect.TypeFor[time.Duration]()
+	netIPType    = reflect.TypeFor[net.IP]()
+	netipType    = reflect.TypeFor[netip.Addr]()
+)
+
+// structFieldType returns the variable type for a field of the provided Go type.
+func structFieldType(t reflect.Type) (pdu.VariableType, error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t.Implements(valueType):
+		// the type is taken from the value
+		return 0, nil
+	case t == durationType:
+		return pdu.VariableTypeTimeTicks, nil
+	case t == netIPType || t == netipType:
+		return pdu.VariableTypeIPAddress, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return pdu.VariableTypeInteger, nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return pdu.VariableTypeGauge32, nil
+	case reflect.Uint, reflect.Uint64:
+		return pdu.VariableTypeCounter64, nil
+	case reflect.String:
+		return pdu.VariableTypeOctetString, nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return pdu.VariableTypeOctetString, nil
+		}
+	}
+	return 0, fmt.Errorf("no variable type for %s, add it to the tag", t)
+}
+
+// structIndexKind returns the index kind for a field with the provided variable type.
+func structIndexKind(t pdu.VariableType, goType reflect.Type) (value.IndexKind, error) {
+	if goType.Kind() == reflect.Pointer {
+		goType = goType.Elem()
+	}
+	if goType.Kind() == reflect.Bool {
+		return 0, fmt.Errorf("type %s can't be used as index", goType)
+	}
+	if t == 0 && goType.Implements(valueType) {
+		if goType.Kind() == reflect.Interface {
+			// the type is only known from the value
+			return 0, fmt.Errorf("no index type for %s, add it to the tag", goType)
+		}
+		t = pdu.VariableType(reflect.Zero(goType).Interface().(value.Value).Type())
+	}
+	switch t {
+	case pdu.VariableTypeInteger:
+		return value.IndexInteger, nil
+	case pdu.VariableTypeCounter32, pdu.VariableTypeGauge32, pdu.VariableTypeTimeTicks:
+		return value.IndexUnsigned, nil
+	case pdu.VariableTypeOctetString, pdu.VariableTypeOpaque:
+		return value.IndexOctetString, nil
+	case pdu.VariableTypeIPAddress:
+		return value.IndexIPAddress, nil
+	case pdu.VariableTypeObjectIdentifier:
+		return value.IndexOID, nil
+	}
+	return 0, fmt.Errorf("type %s can't be used as index", t)
+}
+
+func newStructTable(entry value.OID, slice reflect.Value, fields []structField, options structOptions) (*TableHandler[reflect.Value], error) {
+	table := &TableHandler[reflect.Value]{
+		Entry:        entry,
+		CacheTimeout: options.cacheTimeout,
+		Rows: func(context.Context) ([]reflect.Value, error) {
+			rows := make([]reflect.Value, slice.Len())
+			for i := range rows {
+				rows[i] = slice.Index(i)
+			}
+			return rows, nil
+		},
+	}
+
+	var indexFields [][]int
+	elemType := slice.Type().Elem()
+	for _, field := range fields {
+		if field.table != nil {
+			return nil, fmt.Errorf("column %d: nested tables are not supported", field.id)
+		}
+		fieldIndex := field.index
+		table.Columns = append(table.Columns, Column[reflect.Value]{
+			ID:   field.id,
+			Type: field.t,
+			Value: func(row reflect.Value) any {
+				return fieldInterface(row.FieldByIndex(fieldIndex))
+			},
+		})
+
+		if !field.isIndex {
+			continue
+		}
+		kind, err := structIndexKind(field.t, elemType.FieldByIndex(fieldIndex).Type)
+		if err != nil {
+			return nil, fmt.Errorf("column %d: %w", field.id, err)
+		}
+		table.Index = append(table.Index, value.IndexField{Kind: kind, Implied: field.implied})
+		indexFields = append(indexFields, fieldIndex)
+	}
+	if len(indexFields) == 0 {
+		return nil, fmt.Errorf("no index field")
+	}
+	if err := table.Index.Validate(); err != nil {
+		return nil, err
+	}
+
+	table.RowIndex = func(row reflect.Value) []any {
+		values := make([]any, len(indexFields))
+		for i, fieldIndex := range indexFields {
+			values[i] = fieldInterface(row.FieldByIndex(fieldIndex))
+			if values[i] == nil {
+				// a single row must not break the whole table
+				options.logger.Warn("skipped table row with nil index",
+					slog.String("entry", entry.String()),
+					slog.Int("index_field", i+1),
+				)
+				return nil
+			}
+		}
+		return values
+	}
+	return table, nil
+}
+
+// fieldInterface returns the value of the field. Nil pointers are returned as nil
+// and bools as TruthValue.
+func fieldInterface(field reflect.Value) any {
+	if field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			return nil
+		}
+		field = field.Elem()
+	}
+	if field.Kind() == reflect.Bool && !field.Type().Implements(valueType) {
+		return tc.TruthValue(field.Bool())
+	}
+	return field.Interface()
+}
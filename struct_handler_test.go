@@ -0,0 +1,239 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package agentx_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Olian04/go-agentx"
+	"github.com/Olian04/go-agentx/pdu"
+	"github.com/Olian04/go-agentx/tc"
+	"github.com/Olian04/go-agentx/value"
+)
+
+type testPeer struct {
+	Address  netip.Addr `snmp:"1,index"`
+	Name     string     `snmp:"2,index,implied"`
+	Received uint32     `snmp:"3,counter32"`
+}
+
+type testStats struct {
+	sync.RWMutex
+
+	Requests uint64        `snmp:"1"`
+	Queue    int           `snmp:"2,gauge32"`
+	Uptime   time.Duration `snmp:"3"`
+	Version  *string       `snmp:"4"`
+	Peers    []testPeer    `snmp:"5"`
+	internal int
+}
+
+func TestStructHandler(t *testing.T) {
+	ctx := context.Background()
+	stats := &testStats{
+		Requests: 12,
+		Queue:    3,
+		Uptime:   2 * time.Second,
+		Peers: []testPeer{
+			{Address: netip.MustParseAddr("10.0.0.2"), Name: "b", Received: 5},
+			{Address: netip.MustParseAddr("10.0.0.1"), Name: "a", Received: 7},
+		},
+	}
+	base := value.MustParseOID("1.3.6.1.4.1.45995.3")
+	sh, err := agentx.NewStructHandler(base, stats)
+	require.NoError(t, err)
+
+	oid, typ, v, err := sh.Get(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.2.0"))
+	require.NoError(t, err)
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.3.2.0"), oid)
+	assert.Equal(t, pdu.VariableTypeGauge32, typ)
+	assert.Equal(t, 3, v)
+
+	stats.Lock()
+	stats.Queue = 4
+	stats.Unlock()
+	_, _, v, _ = sh.Get(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.2.0"))
+	assert.Equal(t, 4, v)
+
+	var walked []string
+	from, end := base, value.MustParseOID("1.3.6.1.4.1.45996")
+	for {
+		oid, typ, v, err := sh.GetNext(ctx, from, false, end)
+		require.NoError(t, err)
+		if oid == nil {
+			break
+		}
+		walked = append(walked, fmt.Sprintf("%s %s %v", oid, typ, v))
+		from = oid
+	}
+	assert.Equal(t, []string{
+		"1.3.6.1.4.1.45995.3.1.0 VariableTypeCounter64 12",
+		"1.3.6.1.4.1.45995.3.2.0 VariableTypeGauge32 4",
+		"1.3.6.1.4.1.45995.3.3.0 VariableTypeTimeTicks 2s",
+		"1.3.6.1.4.1.45995.3.5.1.1.10.0.0.1.97 VariableTypeIPAddress 10.0.0.1",
+		"1.3.6.1.4.1.45995.3.5.1.1.10.0.0.2.98 VariableTypeIPAddress 10.0.0.2",
+		"1.3.6.1.4.1.45995.3.5.1.2.10.0.0.1.97 VariableTypeOctetString a",
+		"1.3.6.1.4.1.45995.3.5.1.2.10.0.0.2.98 VariableTypeOctetString b",
+		"1.3.6.1.4.1.45995.3.5.1.3.10.0.0.1.97 VariableTypeCounter32 7",
+		"1.3.6.1.4.1.45995.3.5.1.3.10.0.0.2.98 VariableTypeCounter32 5",
+	}, walked)
+
+	version := "1.0"
+	stats.Version = &version
+	oid, _, v, _ = sh.GetNext(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.3.0"), false, end)
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.3.4.0"), oid)
+	assert.Equal(t, "1.0", v)
+}
+
+func TestStructHandlerInvalid(t *testing.T) {
+	base := value.MustParseOID("1.3.6.1.4.1.45995.3")
+	tests := []struct {
+		name string
+		v    any
+	}{
+		{"no pointer", testStats{}},
+		{"invalid subidentifier", &struct {
+			A int `snmp:"a"`
+		}{}},
+		{"unknown type", &struct {
+			A int `snmp:"1,float"`
+		}{}},
+		{"no derivable type", &struct {
+			A float64 `snmp:"1"`
+		}{}},
+		{"interface index without type", &struct {
+			A []struct {
+				B value.Value `snmp:"1,index"`
+			} `snmp:"1"`
+		}{}},
+		{"bool index", &struct {
+			A []struct {
+				B bool `snmp:"1,index"`
+			} `snmp:"1"`
+		}{}},
+		{"table without index", &struct {
+			A []struct {
+				B int `snmp:"1"`
+			} `snmp:"1"`
+		}{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := agentx.NewStructHandler(base, test.v)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestStructHandlerBool(t *testing.T) {
+	ctx := context.Background()
+	base := value.MustParseOID("1.3.6.1.4.1.45995.3")
+	sh, err := agentx.NewStructHandler(base, &struct {
+		Enabled bool `snmp:"1"`
+		Flag    bool `snmp:"2,gauge32"`
+	}{Enabled: false, Flag: true})
+	require.NoError(t, err)
+
+	_, typ, v, err := sh.Get(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.1.0"))
+	require.NoError(t, err)
+	assert.Equal(t, pdu.VariableTypeInteger, typ)
+	assert.Equal(t, tc.TruthValue(false), v)
+	converted, err := value.Convert(value.Type(typ), v)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), converted.(tc.TruthValue).Int32())
+
+	_, typ, v, err = sh.Get(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.2.0"))
+	require.NoError(t, err)
+	converted, err = value.Convert(value.Type(typ), v)
+	require.NoError(t, err)
+	assert.Equal(t, value.Gauge32(1), converted)
+}
+
+type testPointerRow struct {
+	ID   *value.Integer32 `snmp:"1,index"`
+	Name string           `snmp:"2"`
+}
+
+type testValueRow struct {
+	ID   value.Value `snmp:"1,integer,index"`
+	Name string      `snmp:"2"`
+}
+
+func TestStructHandlerValueIndex(t *testing.T) {
+	ctx := context.Background()
+	id := value.Integer32(7)
+	stats := &struct {
+		Pointers []testPointerRow `snmp:"1"`
+		Values   []testValueRow   `snmp:"2"`
+	}{
+		// rows with a nil index are skipped
+		Pointers: []testPointerRow{{Name: "nil"}, {ID: &id, Name: "pointer"}},
+		Values:   []testValueRow{{ID: value.Integer32(8), Name: "value"}, {Name: "nil"}},
+	}
+
+	var logs bytes.Buffer
+	sh, err := agentx.NewStructHandler(value.MustParseOID("1.3.6.1.4.1.45995.3"), stats,
+		agentx.WithStructLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	require.NoError(t, err)
+
+	_, _, v, err := sh.Get(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.1.1.2.7"))
+	require.NoError(t, err)
+	assert.Equal(t, "pointer", v)
+	_, _, v, err = sh.Get(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.2.1.2.8"))
+	require.NoError(t, err)
+	assert.Equal(t, "value", v)
+	assert.Contains(t, logs.String(), "skipped table row with nil index")
+
+	var walked []string
+	from, end := value.MustParseOID("1.3.6.1.4.1.45995.3"), value.MustParseOID("1.3.6.1.4.1.45996")
+	for {
+		oid, _, v, err := sh.GetNext(ctx, from, false, end)
+		require.NoError(t, err)
+		if oid == nil {
+			break
+		}
+		walked = append(walked, fmt.Sprintf("%s %v", oid, v))
+		from = oid
+	}
+	assert.Equal(t, []string{
+		"1.3.6.1.4.1.45995.3.1.1.1.7 7",
+		"1.3.6.1.4.1.45995.3.1.1.2.7 pointer",
+		"1.3.6.1.4.1.45995.3.2.1.1.8 8",
+		"1.3.6.1.4.1.45995.3.2.1.2.8 value",
+	}, walked)
+}
+
+func TestStructHandlerCache(t *testing.T) {
+	ctx := context.Background()
+	stats := &testStats{Peers: []testPeer{{Address: netip.MustParseAddr("10.0.0.1"), Name: "a"}}}
+	base := value.MustParseOID("1.3.6.1.4.1.45995.3")
+	cached, err := agentx.NewStructHandler(base, stats, agentx.WithStructCacheTimeout(time.Hour))
+	require.NoError(t, err)
+	uncached, err := agentx.NewStructHandler(base, stats)
+	require.NoError(t, err)
+	oid := value.MustParseOID("1.3.6.1.4.1.45995.3.5.1.2.10.0.0.2.98")
+
+	_, typ, _, err := cached.Get(ctx, oid)
+	require.NoError(t, err)
+	assert.Equal(t, pdu.VariableTypeNoSuchInstance, typ)
+
+	stats.Lock()
+	stats.Peers = append(stats.Peers, testPeer{Address: netip.MustParseAddr("10.0.0.2"), Name: "b"})
+	stats.Unlock()
+	_, typ, _, _ = cached.Get(ctx, oid)
+	assert.Equal(t, pdu.VariableTypeNoSuchInstance, typ)
+	_, _, v, _ := uncached.Get(ctx, oid)
+	assert.Equal(t, "b", v)
+}
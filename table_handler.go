@@ -0,0 +1,204 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package agentx
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Olian04/go-agentx/pdu"
+	"github.com/Olian04/go-agentx/value"
+)
+
+// Column defines a column of a table, that is served by a TableHandler.
+type Column[R any] struct {
+	// ID defines the subidentifier of the column below the table entry.
+	ID uint32
+	// Type defines the type of the column. It can be zero, if Value returns
+	// value.Value values.
+	Type pdu.VariableType
+	// Value returns the value of the column in the provided row. The value is
+	// converted like the values returned by a Handler. A nil value marks the
+	// cell as absent.
+	Value func(R) any
+}
+
+// TableHandler is a helper that serves a conceptual table (RFC 2578). The rows
+// are requested from the provider and the oids of the cells are built from the
+// entry oid, the column id and the encoded row index. Cells are served column
+// by column in lexicographic order.
+//
+// Without CacheTimeout, the rows are requested, their indexes encoded and sorted
+// for each variable of a request. A walk of a table with C columns and R rows
+// costs O(C·R²·log R) then, so large tables should set CacheTimeout.
+type TableHandler[R any] struct {
+	// Entry defines the oid of the table entry (e.g. 1.3.6.1.2.1.2.2.1 for ifEntry).
+	Entry value.OID
+	// Index defines the schema of the row index.
+	Index value.IndexSchema
+	// RowIndex returns the index values of the provided row (see value.IndexSchema).
+	// Rows, for which it returns nil, are not served.
+	RowIndex func(R) []any
+	// Columns defines the columns of the table.
+	Columns []Column[R]
+	// Rows returns the current rows of the table in any order.
+	Rows func(context.Context) ([]R, error)
+	// CacheTimeout enables the caching of the rows, if set. The rows are then
+	// requested at most once per timeout and changes of the rows become visible
+	// with the next request after the timeout.
+	CacheTimeout time.Duration
+
+	mu           sync.Mutex
+	cache        []tableRow[R]
+	cacheExpires time.Time
+}
+
+// StaticRows returns a row provider for the Rows field of a TableHandler, that
+// always returns the provided rows.
+func StaticRows[R any](rows ...R) func(context.Context) ([]R, error) {
+	return func(context.Context) ([]R, error) {
+		return rows, nil
+	}
+}
+
+type tableRow[R any] struct {
+	index value.OID
+	row   R
+}
+
+// Get tries to find the provided oid and returns the corresponding value.
+func (t *TableHandler[R]) Get(ctx context.Context, oid value.OID) (value.OID, pdu.VariableType, any, error) {
+	if !oid.IsChildOf(t.Entry) {
+		return nil, pdu.VariableTypeNoSuchObject, nil, nil
+	}
+	columnID, index := oid[len(t.Entry)], oid[len(t.Entry)+1:]
+	column := slices.IndexFunc(t.Columns, func(c Column[R]) bool { return c.ID == columnID })
+	if column < 0 {
+		return nil, pdu.VariableTypeNoSuchObject, nil, nil
+	}
+	// the column exists, missing rows and absent cells are missing instances
+	if len(index) == 0 {
+		return nil, pdu.VariableTypeNoSuchInstance, nil, nil
+	}
+
+	rows, err := t.rows(ctx)
+	if err != nil {
+		return nil, pdu.VariableTypeNoSuchObject, nil, err
+	}
+	i, found := slices.BinarySearchFunc(rows, index, func(r tableRow[R], index value.OID) int {
+		return r.index.Compare(index)
+	})
+	if !found {
+		return nil, pdu.VariableTypeNoSuchInstance, nil, nil
+	}
+	v := t.Columns[column].Value(rows[i].row)
+	if v == nil {
+		return nil, pdu.VariableTypeNoSuchInstance, nil, nil
+	}
+	return oid, t.Columns[column].Type, v, nil
+}
+
+// GetNext tries to find the value that follows the provided oid and returns it.
+func (t *TableHandler[R]) GetNext(ctx context.Context, from value.OID, includeFrom bool, to value.OID) (value.OID, pdu.VariableType, any, error) {
+	// position of from inside the table: the column id and the row index
+	var (
+		columnID uint32
+		index    value.OID
+		start    = true
+	)
+	switch {
+	case from.IsChildOf(t.Entry):
+		columnID, index, start = from[len(t.Entry)], from[len(t.Entry)+1:], false
+	case from.Compare(t.Entry) > 0:
+		// behind the table
+		return nil, pdu.VariableTypeNoSuchObject, nil, nil
+	}
+
+	rows, err := t.rows(ctx)
+	if err != nil {
+		return nil, pdu.VariableTypeNoSuchObject, nil, err
+	}
+	columns := slices.SortedFunc(slices.Values(t.Columns), func(a, b Column[R]) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	for _, column := range columns {
+		first := 0
+		if !start {
+			if column.ID < columnID {
+				continue
+			}
+			if column.ID == columnID {
+				first, _ = slices.BinarySearchFunc(rows, index, func(r tableRow[R], index value.OID) int {
+					return r.index.Compare(index)
+				})
+				if first < len(rows) && !includeFrom && rows[first].index.Equal(index) {
+					first++
+				}
+			}
+		}
+
+		for _, row := range rows[first:] {
+			oid := t.Entry.Append(column.ID).Append(row.index...)
+			if value.CompareOIDs(oid, to) != -1 {
+				return nil, pdu.VariableTypeNoSuchObject, nil, nil
+			}
+			if v := column.Value(row.row); v != nil {
+				return oid, column.Type, v, nil
+			}
+		}
+	}
+	return nil, pdu.VariableTypeNoSuchObject, nil, nil
+}
+
+// rows returns the rows of the provider with their encoded index, sorted by index.
+// The returned rows must not be modified.
+func (t *TableHandler[R]) rows(ctx context.Context) ([]tableRow[R], error) {
+	if t.CacheTimeout <= 0 {
+		return t.loadRows(ctx)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	if t.cache != nil && now.Before(t.cacheExpires) {
+		return t.cache, nil
+	}
+	rows, err := t.loadRows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t.cache, t.cacheExpires = rows, now.Add(t.CacheTimeout)
+	return rows, nil
+}
+
+// loadRows requests the rows from the provider and encodes and sorts them.
+func (t *TableHandler[R]) loadRows(ctx context.Context) ([]tableRow[R], error) {
+	rows, err := t.Rows(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]tableRow[R], 0, len(rows))
+	for _, row := range rows {
+		values := t.RowIndex(row)
+		if values == nil {
+			continue
+		}
+		index, err := t.Index.Encode(values...)
+		if err != nil {
+			return nil, fmt.Errorf("table %s: %w", t.Entry, err)
+		}
+		result = append(result, tableRow[R]{index: index, row: row})
+	}
+	slices.SortStableFunc(result, func(a, b tableRow[R]) int {
+		return a.index.Compare(b.index)
+	})
+	return result, nil
+}
@@ -0,0 +1,171 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package agentx_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Olian04/go-agentx"
+	"github.com/Olian04/go-agentx/pdu"
+	"github.com/Olian04/go-agentx/value"
+)
+
+type testInterface struct {
+	index int
+	name  string
+	speed uint32
+}
+
+func testTableHandler(rows ...testInterface) *agentx.TableHandler[testInterface] {
+	return &agentx.TableHandler[testInterface]{
+		Entry: value.MustParseOID("1.3.6.1.4.1.45995.3.1.1"),
+		Index: value.IndexSchema{{Kind: value.IndexInteger}},
+		RowIndex: func(row testInterface) []any {
+			return []any{row.index}
+		},
+		Columns: []agentx.Column[testInterface]{
+			{ID: 2, Type: pdu.VariableTypeOctetString, Value: func(row testInterface) any { return row.name }},
+			{ID: 1, Type: pdu.VariableTypeInteger, Value: func(row testInterface) any { return row.index }},
+			{ID: 3, Type: pdu.VariableTypeGauge32, Value: func(row testInterface) any {
+				if row.speed == 0 {
+					return nil
+				}
+				return row.speed
+			}},
+		},
+		Rows: agentx.StaticRows(rows...),
+	}
+}
+
+func TestTableHandlerGet(t *testing.T) {
+	ctx := context.Background()
+	th := testTableHandler(testInterface{2, "eth1", 1000}, testInterface{1, "lo", 0})
+
+	oid, typ, v, err := th.Get(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.1.1.2.2"))
+	require.NoError(t, err)
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.3.1.1.2.2"), oid)
+	assert.Equal(t, pdu.VariableTypeOctetString, typ)
+	assert.Equal(t, "eth1", v)
+
+	for missing, expected := range map[string]pdu.VariableType{
+		"1.3.6.1.4.1.45995.3.1.1.2.3": pdu.VariableTypeNoSuchInstance,
+		"1.3.6.1.4.1.45995.3.1.1.3.1": pdu.VariableTypeNoSuchInstance,
+		"1.3.6.1.4.1.45995.3.1.1.2":   pdu.VariableTypeNoSuchInstance,
+		"1.3.6.1.4.1.45995.3.1.1.4.1": pdu.VariableTypeNoSuchObject,
+		"1.3.6.1.4.1.45995.3.1.1":     pdu.VariableTypeNoSuchObject,
+		"1.3.6.1.4.1.45995.3.2":       pdu.VariableTypeNoSuchObject,
+	} {
+		oid, typ, _, err := th.Get(ctx, value.MustParseOID(missing))
+		require.NoError(t, err)
+		assert.Nil(t, oid, missing)
+		assert.Equal(t, expected, typ, missing)
+	}
+}
+
+func TestTableHandlerWalk(t *testing.T) {
+	ctx := context.Background()
+	th := testTableHandler(testInterface{2, "eth1", 1000}, testInterface{10, "eth9", 100}, testInterface{1, "lo", 0})
+
+	var walked []string
+	from, end := value.MustParseOID("1.3.6.1.4.1.45995.3"), value.MustParseOID("1.3.6.1.4.1.45996")
+	for {
+		oid, _, v, err := th.GetNext(ctx, from, false, end)
+		require.NoError(t, err)
+		if oid == nil {
+			break
+		}
+		walked = append(walked, fmt.Sprintf("%s=%v", oid, v))
+		from = oid
+	}
+	assert.Equal(t, []string{
+		"1.3.6.1.4.1.45995.3.1.1.1.1=1",
+		"1.3.6.1.4.1.45995.3.1.1.1.2=2",
+		"1.3.6.1.4.1.45995.3.1.1.1.10=10",
+		"1.3.6.1.4.1.45995.3.1.1.2.1=lo",
+		"1.3.6.1.4.1.45995.3.1.1.2.2=eth1",
+		"1.3.6.1.4.1.45995.3.1.1.2.10=eth9",
+		"1.3.6.1.4.1.45995.3.1.1.3.2=1000",
+		"1.3.6.1.4.1.45995.3.1.1.3.10=100",
+	}, walked)
+}
+
+func TestTableHandlerGetNextBounds(t *testing.T) {
+	ctx := context.Background()
+	th := testTableHandler(testInterface{1, "lo", 0}, testInterface{2, "eth1", 1000})
+	end := value.MustParseOID("1.3.6.1.4.1.45996")
+
+	oid, _, _, _ := th.GetNext(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.1.1.2.1"), true, end)
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.3.1.1.2.1"), oid)
+	oid, _, _, _ = th.GetNext(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.1.1.2.1.5"), false, end)
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.3.1.1.2.2"), oid)
+	oid, _, _, _ = th.GetNext(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.1.1.2"), false, end)
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.3.1.1.2.1"), oid)
+	oid, _, _, _ = th.GetNext(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.1.2"), false, end)
+	assert.Nil(t, oid)
+	oid, _, _, _ = th.GetNext(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3"), false, value.MustParseOID("1.3.6.1.4.1.45995.3.1.1.1.2"))
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.3.1.1.1.1"), oid)
+	oid, _, _, _ = th.GetNext(ctx, oid, false, value.MustParseOID("1.3.6.1.4.1.45995.3.1.1.1.2"))
+	assert.Nil(t, oid)
+}
+
+func TestTableHandlerInvalidIndex(t *testing.T) {
+	th := testTableHandler(testInterface{-1, "invalid", 0})
+	_, _, _, err := th.GetNext(context.Background(), value.MustParseOID("1.3.6.1.4.1.45995.3"), false, value.MustParseOID("1.3.6.1.4.1.45996"))
+	assert.ErrorIs(t, err, value.ErrInvalidIndex)
+}
+
+func TestTableHandlerCache(t *testing.T) {
+	ctx := context.Background()
+	th := testTableHandler()
+	rows, calls := []testInterface{{1, "lo", 0}}, 0
+	th.Rows = func(context.Context) ([]testInterface, error) {
+		calls++
+		return rows, nil
+	}
+	th.CacheTimeout = 100 * time.Millisecond
+
+	from, end := value.MustParseOID("1.3.6.1.4.1.45995.3"), value.MustParseOID("1.3.6.1.4.1.45996")
+	for oid := from; oid != nil; {
+		oid, _, _, _ = th.GetNext(ctx, oid, false, end)
+	}
+	assert.Equal(t, 1, calls)
+
+	// changes become visible after the timeout
+	rows = append(rows, testInterface{2, "eth1", 1000})
+	oid, _, _, _ := th.Get(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.1.1.2.2"))
+	assert.Nil(t, oid)
+	time.Sleep(150 * time.Millisecond)
+	oid, _, _, _ = th.Get(ctx, value.MustParseOID("1.3.6.1.4.1.45995.3.1.1.2.2"))
+	assert.Equal(t, value.MustParseOID("1.3.6.1.4.1.45995.3.1.1.2.2"), oid)
+	assert.Equal(t, 2, calls)
+}
+
+func BenchmarkTableHandlerWalk(b *testing.B) {
+	for _, cacheTimeout := range []time.Duration{0, time.Minute} {
+		b.Run(fmt.Sprintf("CacheTimeout=%s", cacheTimeout), func(b *testing.B) {
+			rows := make([]testInterface, 1000)
+			for i := range rows {
+				rows[i] = testInterface{i + 1, fmt.Sprintf("eth%d", i), 1000}
+			}
+			th := testTableHandler(rows...)
+			th.CacheTimeout = cacheTimeout
+			ctx := context.Background()
+			from, end := value.MustParseOID("1.3.6.1.4.1.45995.3"), value.MustParseOID("1.3.6.1.4.1.45996")
+
+			b.ReportAllocs()
+			for b.Loop() {
+				for oid := from; oid != nil; {
+					oid, _, _, _ = th.GetNext(ctx, oid, false, end)
+				}
+			}
+		})
+	}
+}
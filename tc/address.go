@@ -0,0 +1,78 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package tc
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/Olian04/go-agentx/value"
+)
+
+// MacAddress defines a MacAddress value (RFC 2579). It is encoded as a
+// 6 byte octet string.
+type MacAddress net.HardwareAddr
+
+// Type returns value.TypeOctetString.
+func (m MacAddress) Type() value.Type { return value.TypeOctetString }
+
+// ByteSize returns the number of bytes, the value would need in the encoded version.
+func (m MacAddress) ByteSize() int { return value.OctetString(m).ByteSize() }
+
+// AppendBinary appends the encoded value to b and returns the extended slice.
+func (m MacAddress) AppendBinary(b []byte) ([]byte, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return value.OctetString(m).AppendBinary(b)
+}
+
+// Validate returns an error, if the address isn't 6 bytes long.
+func (m MacAddress) Validate() error {
+	if len(m) != 6 {
+		return fmt.Errorf("%w: MacAddress of %d bytes", ErrInvalidValue, len(m))
+	}
+	return nil
+}
+
+func (m MacAddress) String() string {
+	return net.HardwareAddr(m).String()
+}
+
+// ParseMacAddress returns the hardware address of the provided MacAddress octets.
+func ParseMacAddress(b []byte) (net.HardwareAddr, error) {
+	if err := MacAddress(b).Validate(); err != nil {
+		return nil, err
+	}
+	return net.HardwareAddr(clone(b)), nil
+}
+
+// PhysAddress defines a PhysAddress value (RFC 2579), a media- or physical-level
+// address of any length. It is encoded as an octet string.
+type PhysAddress net.HardwareAddr
+
+// Type returns value.TypeOctetString.
+func (p PhysAddress) Type() value.Type { return value.TypeOctetString }
+
+// ByteSize returns the number of bytes, the value would need in the encoded version.
+func (p PhysAddress) ByteSize() int { return value.OctetString(p).ByteSize() }
+
+// AppendBinary appends the encoded value to b and returns the extended slice.
+func (p PhysAddress) AppendBinary(b []byte) ([]byte, error) {
+	return value.OctetString(p).AppendBinary(b)
+}
+
+func (p PhysAddress) String() string {
+	return net.HardwareAddr(p).String()
+}
+
+// ParsePhysAddress returns the hardware address of the provided PhysAddress octets.
+func ParsePhysAddress(b []byte) net.HardwareAddr {
+	return net.HardwareAddr(clone(b))
+}
+
+func clone(b []byte) []byte {
+	return append([]byte(nil), b...)
+}
@@ -0,0 +1,72 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package tc
+
+import (
+	"github.com/Olian04/go-agentx/value"
+)
+
+// Bits defines a BITS value (RFC 2578). It is encoded as an octet string, where
+// bit 0 is the most significant bit of the first octet. The zero value is an
+// empty set.
+type Bits []byte
+
+// NewBits returns the set of the provided bit numbers.
+func NewBits(bits ...uint) Bits {
+	var b Bits
+	for _, bit := range bits {
+		b = b.Set(bit)
+	}
+	return b
+}
+
+// Set returns the set with the provided bit number set. The set is extended
+// if needed.
+func (b Bits) Set(bit uint) Bits {
+	for uint(len(b)) <= bit/8 {
+		b = append(b, 0)
+	}
+	b[bit/8] |= 0x80 >> (bit % 8)
+	return b
+}
+
+// Clear clears the provided bit number.
+func (b Bits) Clear(bit uint) {
+	if bit/8 < uint(len(b)) {
+		b[bit/8] &^= 0x80 >> (bit % 8)
+	}
+}
+
+// IsSet returns true, if the provided bit number is set.
+func (b Bits) IsSet(bit uint) bool {
+	return bit/8 < uint(len(b)) && b[bit/8]&(0x80>>(bit%8)) != 0
+}
+
+// Numbers returns the set bit numbers in ascending order.
+func (b Bits) Numbers() []uint {
+	var numbers []uint
+	for bit := uint(0); bit < uint(len(b))*8; bit++ {
+		if b.IsSet(bit) {
+			numbers = append(numbers, bit)
+		}
+	}
+	return numbers
+}
+
+// Type returns value.TypeOctetString.
+func (b Bits) Type() value.Type { return value.TypeOctetString }
+
+// ByteSize returns the number of bytes, the value would need in the encoded version.
+func (b Bits) ByteSize() int { return value.OctetString(b).ByteSize() }
+
+// AppendBinary appends the encoded value to b and returns the extended slice.
+func (b Bits) AppendBinary(dst []byte) ([]byte, error) {
+	return value.OctetString(b).AppendBinary(dst)
+}
+
+// ParseBits returns the set of the provided BITS octets.
+func ParseBits(b []byte) Bits {
+	return Bits(clone(b))
+}
@@ -0,0 +1,84 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package tc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/Olian04/go-agentx/value"
+)
+
+// DateAndTime defines a DateAndTime value (RFC 2579). It is encoded as an
+// 11 byte octet string, including the offset of the time zone to UTC.
+type DateAndTime time.Time
+
+// Time returns the value as time.Time.
+func (d DateAndTime) Time() time.Time { return time.Time(d) }
+
+// Type returns value.TypeOctetString.
+func (d DateAndTime) Type() value.Type { return value.TypeOctetString }
+
+// ByteSize returns the number of bytes, the value would need in the encoded version.
+func (d DateAndTime) ByteSize() int { return 4 + 12 }
+
+// AppendBinary appends the encoded value to b and returns the extended slice.
+func (d DateAndTime) AppendBinary(b []byte) ([]byte, error) {
+	t := time.Time(d)
+	if t.Year() < 0 || t.Year() > 65535 {
+		return nil, fmt.Errorf("%w: year %d out of range", ErrInvalidValue, t.Year())
+	}
+
+	_, offset := t.Zone()
+	direction := byte('+')
+	if offset < 0 {
+		direction, offset = '-', -offset
+	}
+
+	b = binary.LittleEndian.AppendUint32(b, 11)
+	b = binary.BigEndian.AppendUint16(b, uint16(t.Year()))
+	b = append(b,
+		byte(t.Month()), byte(t.Day()),
+		byte(t.Hour()), byte(t.Minute()), byte(t.Second()), byte(t.Nanosecond()/int(100*time.Millisecond)),
+		direction, byte(offset/3600), byte(offset%3600/60),
+		0, // padding byte
+	)
+	return b, nil
+}
+
+func (d DateAndTime) String() string {
+	return time.Time(d).String()
+}
+
+// ParseDateAndTime returns the time of the provided DateAndTime octets (RFC 2579).
+// Values without time zone information (8 bytes) are returned in the local time zone.
+func ParseDateAndTime(b []byte) (time.Time, error) {
+	if len(b) != 8 && len(b) != 11 {
+		return time.Time{}, fmt.Errorf("%w: DateAndTime of %d bytes", ErrInvalidValue, len(b))
+	}
+
+	year := int(binary.BigEndian.Uint16(b))
+	month, day, hour, minute, second, deciSecond := b[2], b[3], b[4], b[5], b[6], b[7]
+	if month < 1 || month > 12 || day < 1 || day > 31 || hour > 23 || minute > 59 || second > 60 || deciSecond > 9 {
+		return time.Time{}, fmt.Errorf("%w: DateAndTime % x out of range", ErrInvalidValue, b)
+	}
+
+	location := time.Local
+	if len(b) == 11 {
+		direction, hours, minutes := b[8], b[9], b[10]
+		if direction != '+' && direction != '-' || hours > 14 || minutes > 59 {
+			return time.Time{}, fmt.Errorf("%w: DateAndTime zone % x out of range", ErrInvalidValue, b[8:])
+		}
+		offset := int(hours)*3600 + int(minutes)*60
+		if direction == '-' {
+			offset = -offset
+		}
+		location = time.FixedZone("", offset)
+	}
+
+	return time.Date(year, time.Month(month), int(day), int(hour), int(minute), int(second),
+		int(deciSecond)*int(100*time.Millisecond), location), nil
+}
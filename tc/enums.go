@@ -0,0 +1,149 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package tc
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/Olian04/go-agentx/value"
+)
+
+// TruthValue defines a TruthValue value (RFC 2579). It is encoded as an
+// Integer32, true is 1 and false is 2.
+type TruthValue bool
+
+// Type returns value.TypeInteger.
+func (tv TruthValue) Type() value.Type { return value.TypeInteger }
+
+// ByteSize returns the number of bytes, the value would need in the encoded version.
+func (tv TruthValue) ByteSize() int { return 4 }
+
+// AppendBinary appends the encoded value to b and returns the extended slice.
+func (tv TruthValue) AppendBinary(b []byte) ([]byte, error) {
+	return binary.LittleEndian.AppendUint32(b, uint32(tv.Int32())), nil
+}
+
+// Int32 returns the integer representation of the value.
+func (tv TruthValue) Int32() int32 {
+	if tv {
+		return 1
+	}
+	return 2
+}
+
+// ParseTruthValue returns the boolean of the provided TruthValue integer.
+func ParseTruthValue(i int32) (bool, error) {
+	switch i {
+	case 1:
+		return true, nil
+	case 2:
+		return false, nil
+	}
+	return false, fmt.Errorf("%w: TruthValue %d", ErrInvalidValue, i)
+}
+
+// The states of the RowStatus textual convention (RFC 2579).
+const (
+	RowStatusActive        RowStatus = 1
+	RowStatusNotInService  RowStatus = 2
+	RowStatusNotReady      RowStatus = 3
+	RowStatusCreateAndGo   RowStatus = 4
+	RowStatusCreateAndWait RowStatus = 5
+	RowStatusDestroy       RowStatus = 6
+)
+
+// RowStatus defines a RowStatus value (RFC 2579). It is encoded as an Integer32.
+type RowStatus int32
+
+// Type returns value.TypeInteger.
+func (rs RowStatus) Type() value.Type { return value.TypeInteger }
+
+// ByteSize returns the number of bytes, the value would need in the encoded version.
+func (rs RowStatus) ByteSize() int { return 4 }
+
+// AppendBinary appends the encoded value to b and returns the extended slice.
+func (rs RowStatus) AppendBinary(b []byte) ([]byte, error) {
+	if err := rs.Validate(); err != nil {
+		return nil, err
+	}
+	return binary.LittleEndian.AppendUint32(b, uint32(rs)), nil
+}
+
+// Validate returns an error, if the value isn't a defined state.
+func (rs RowStatus) Validate() error {
+	if rs < RowStatusActive || rs > RowStatusDestroy {
+		return fmt.Errorf("%w: RowStatus %d", ErrInvalidValue, int32(rs))
+	}
+	return nil
+}
+
+func (rs RowStatus) String() string {
+	switch rs {
+	case RowStatusActive:
+		return "active"
+	case RowStatusNotInService:
+		return "notInService"
+	case RowStatusNotReady:
+		return "notReady"
+	case RowStatusCreateAndGo:
+		return "createAndGo"
+	case RowStatusCreateAndWait:
+		return "createAndWait"
+	case RowStatusDestroy:
+		return "destroy"
+	}
+	return fmt.Sprintf("RowStatusUnknown (%d)", int32(rs))
+}
+
+// The storage types of the StorageType textual convention (RFC 2579).
+const (
+	StorageTypeOther       StorageType = 1
+	StorageTypeVolatile    StorageType = 2
+	StorageTypeNonVolatile StorageType = 3
+	StorageTypePermanent   StorageType = 4
+	StorageTypeReadOnly    StorageType = 5
+)
+
+// StorageType defines a StorageType value (RFC 2579). It is encoded as an Integer32.
+type StorageType int32
+
+// Type returns value.TypeInteger.
+func (st StorageType) Type() value.Type { return value.TypeInteger }
+
+// ByteSize returns the number of bytes, the value would need in the encoded version.
+func (st StorageType) ByteSize() int { return 4 }
+
+// AppendBinary appends the encoded value to b and returns the extended slice.
+func (st StorageType) AppendBinary(b []byte) ([]byte, error) {
+	if err := st.Validate(); err != nil {
+		return nil, err
+	}
+	return binary.LittleEndian.AppendUint32(b, uint32(st)), nil
+}
+
+// Validate returns an error, if the value isn't a defined storage type.
+func (st StorageType) Validate() error {
+	if st < StorageTypeOther || st > StorageTypeReadOnly {
+		return fmt.Errorf("%w: StorageType %d", ErrInvalidValue, int32(st))
+	}
+	return nil
+}
+
+func (st StorageType) String() string {
+	switch st {
+	case StorageTypeOther:
+		return "other"
+	case StorageTypeVolatile:
+		return "volatile"
+	case StorageTypeNonVolatile:
+		return "nonVolatile"
+	case StorageTypePermanent:
+		return "permanent"
+	case StorageTypeReadOnly:
+		return "readOnly"
+	}
+	return fmt.Sprintf("StorageTypeUnknown (%d)", int32(st))
+}
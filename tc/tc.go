@@ -0,0 +1,28 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+// Package tc provides the textual conventions of SMIv2 (RFC 2579) as typed
+// values. They implement value.Value and can be used in a ListItem or returned
+// by any Handler.
+package tc
+
+import (
+	"errors"
+
+	"github.com/Olian04/go-agentx/value"
+)
+
+// ErrInvalidValue is returned (wrapped), if a value doesn't match its textual convention.
+var ErrInvalidValue = errors.New("invalid textual convention value")
+
+// DisplayString defines a DisplayString value (RFC 2579).
+type DisplayString = value.DisplayString
+
+// ParseDisplayString returns the text of the provided DisplayString octets.
+func ParseDisplayString(b []byte) (string, error) {
+	if err := DisplayString(b).Validate(); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
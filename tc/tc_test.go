@@ -0,0 +1,136 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package tc_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Olian04/go-agentx/tc"
+	"github.com/Olian04/go-agentx/value"
+)
+
+func TestEncoding(t *testing.T) {
+	zone := time.FixedZone("", -(4*3600 + 30*60))
+	tests := []struct {
+		name     string
+		value    value.Value
+		typ      value.Type
+		expected []byte
+	}{
+		{"DateAndTime", tc.DateAndTime(time.Date(1992, 5, 26, 13, 30, 15, 500*int(time.Millisecond), zone)), value.TypeOctetString,
+			[]byte{11, 0, 0, 0, 0x07, 0xc8, 5, 26, 13, 30, 15, 5, '-', 4, 30, 0}},
+		{"TruthValue true", tc.TruthValue(true), value.TypeInteger, []byte{1, 0, 0, 0}},
+		{"TruthValue false", tc.TruthValue(false), value.TypeInteger, []byte{2, 0, 0, 0}},
+		{"RowStatus", tc.RowStatusCreateAndWait, value.TypeInteger, []byte{5, 0, 0, 0}},
+		{"StorageType", tc.StorageTypeNonVolatile, value.TypeInteger, []byte{3, 0, 0, 0}},
+		{"MacAddress", tc.MacAddress{0x00, 0x1b, 0x21, 0xff, 0x00, 0x80}, value.TypeOctetString,
+			[]byte{6, 0, 0, 0, 0x00, 0x1b, 0x21, 0xff, 0x00, 0x80, 0, 0}},
+		{"PhysAddress", tc.PhysAddress{1, 2}, value.TypeOctetString, []byte{2, 0, 0, 0, 1, 2, 0, 0}},
+		{"Bits", tc.NewBits(0, 9), value.TypeOctetString, []byte{2, 0, 0, 0, 0x80, 0x40, 0, 0}},
+		{"DisplayString", tc.DisplayString("up"), value.TypeOctetString, []byte{2, 0, 0, 0, 'u', 'p', 0, 0}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.typ, test.value.Type())
+			data, err := test.value.AppendBinary(nil)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, data)
+			assert.Equal(t, len(test.expected), test.value.ByteSize())
+		})
+	}
+}
+
+func TestInvalidValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		value value.Value
+	}{
+		{"RowStatus", tc.RowStatus(7)},
+		{"StorageType", tc.StorageType(0)},
+		{"MacAddress", tc.MacAddress{1, 2, 3}},
+		{"DateAndTime", tc.DateAndTime(time.Date(-1, 1, 1, 0, 0, 0, 0, time.UTC))},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.value.AppendBinary(nil)
+			assert.ErrorIs(t, err, tc.ErrInvalidValue)
+		})
+	}
+}
+
+func TestParseDateAndTime(t *testing.T) {
+	parsed, err := tc.ParseDateAndTime([]byte{0x07, 0xc8, 5, 26, 13, 30, 15, 5, '+', 2, 0})
+	require.NoError(t, err)
+	expected := time.Date(1992, 5, 26, 11, 30, 15, 500*int(time.Millisecond), time.UTC)
+	assert.True(t, expected.Equal(parsed), "expected %s, got %s", expected, parsed)
+	_, offset := parsed.Zone()
+	assert.Equal(t, 7200, offset)
+
+	parsed, err = tc.ParseDateAndTime([]byte{0x07, 0xc8, 5, 26, 13, 30, 15, 5})
+	require.NoError(t, err)
+	assert.Equal(t, time.Local, parsed.Location())
+	assert.Equal(t, 13, parsed.Hour())
+
+	now := time.Now().Truncate(100 * time.Millisecond)
+	data, err := tc.DateAndTime(now).AppendBinary(nil)
+	require.NoError(t, err)
+	parsed, err = tc.ParseDateAndTime(data[4:15])
+	require.NoError(t, err)
+	assert.True(t, now.Equal(parsed), "expected %s, got %s", now, parsed)
+
+	for _, invalid := range [][]byte{
+		{0x07, 0xc8, 5, 26, 13, 30, 15},
+		{0x07, 0xc8, 13, 26, 13, 30, 15, 5},
+		{0x07, 0xc8, 5, 26, 13, 30, 15, 5, '*', 2, 0},
+	} {
+		_, err := tc.ParseDateAndTime(invalid)
+		assert.ErrorIs(t, err, tc.ErrInvalidValue)
+	}
+}
+
+func TestParseTruthValue(t *testing.T) {
+	b, err := tc.ParseTruthValue(1)
+	require.NoError(t, err)
+	assert.True(t, b)
+	b, err = tc.ParseTruthValue(2)
+	require.NoError(t, err)
+	assert.False(t, b)
+	_, err = tc.ParseTruthValue(0)
+	assert.ErrorIs(t, err, tc.ErrInvalidValue)
+}
+
+func TestParseAddresses(t *testing.T) {
+	mac, err := tc.ParseMacAddress([]byte{0x00, 0x1b, 0x21, 0xff, 0x00, 0x80})
+	require.NoError(t, err)
+	assert.Equal(t, "00:1b:21:ff:00:80", mac.String())
+	_, err = tc.ParseMacAddress([]byte{1})
+	assert.ErrorIs(t, err, tc.ErrInvalidValue)
+
+	assert.Equal(t, net.HardwareAddr{1, 2, 3}, tc.ParsePhysAddress([]byte{1, 2, 3}))
+}
+
+func TestBits(t *testing.T) {
+	bits := tc.NewBits(1, 3, 12)
+	assert.True(t, bits.IsSet(3))
+	assert.False(t, bits.IsSet(2))
+	assert.False(t, bits.IsSet(100))
+	assert.Equal(t, []uint{1, 3, 12}, bits.Numbers())
+
+	bits.Clear(3)
+	assert.Equal(t, []uint{1, 12}, tc.ParseBits(bits).Numbers())
+}
+
+func TestParseDisplayString(t *testing.T) {
+	text, err := tc.ParseDisplayString([]byte("eth0"))
+	require.NoError(t, err)
+	assert.Equal(t, "eth0", text)
+	_, err = tc.ParseDisplayString(make([]byte, value.MaxDisplayStringLength+1))
+	assert.ErrorIs(t, err, value.ErrTooLong)
+}
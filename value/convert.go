@@ -0,0 +1,231 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package value
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/netip"
+	"reflect"
+	"time"
+)
+
+// ErrConversion is returned (wrapped) by Convert, if a value can't be converted
+// into the requested type.
+var ErrConversion = errors.New("value conversion failed")
+
+// Convert converts the provided Go value into a typed value of the provided type.
+// Values that are already of the requested type are returned unchanged. Otherwise
+// the following conversions are done, if the converted value fits into the range
+// of the requested type:
+//
+//   - TypeInteger: all integer and floating point types (floats are rounded).
+//     Booleans are rejected, as their encoding depends on the object, use
+//     tc.TruthValue for TruthValue objects.
+//   - TypeCounter32, TypeGauge32, TypeCounter64: all integer and floating point
+//     types (floats are rounded) and bool (true is 1, false is 0).
+//   - TypeTimeTicks: time.Duration, time.Time (the time since then) and all
+//     integer types (in hundredths of a second).
+//   - TypeOctetString, TypeOpaque: string, []byte and fmt.Stringer. Both are
+//     limited to MaxOctetStringLength bytes.
+//   - TypeObjectIdentifier: OID, []uint32 and string.
+//   - TypeIPAddress: net.IP, netip.Addr, [4]byte and string.
+//
+// Named types with one of the listed underlying types are accepted as well.
+func Convert(t Type, v any) (Value, error) {
+	if typed, ok := v.(Value); ok && typed.Type() == t {
+		if validator, ok := typed.(validator); ok {
+			if err := validator.Validate(); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrConversion, err)
+			}
+		}
+		return typed, nil
+	}
+	if v == nil {
+		return nil, conversionError(t, v, "no value")
+	}
+
+	switch t {
+	case TypeInteger:
+		i, err := convertSigned(t, v, math.MinInt32, math.MaxInt32)
+		if err != nil {
+			return nil, err
+		}
+		return Integer32(i), nil
+	case TypeOctetString:
+		octets, err := convertOctets(t, v)
+		if err != nil {
+			return nil, err
+		}
+		if len(octets) > MaxOctetStringLength {
+			return nil, conversionError(t, v, "too long")
+		}
+		return OctetString(octets), nil
+	case TypeObjectIdentifier:
+		return convertOID(t, v)
+	case TypeIPAddress:
+		return convertIPAddress(t, v)
+	case TypeCounter32:
+		u, err := convertUnsigned(t, v, math.MaxUint32)
+		if err != nil {
+			return nil, err
+		}
+		return Counter32(u), nil
+	case TypeGauge32:
+		u, err := convertUnsigned(t, v, math.MaxUint32)
+		if err != nil {
+			return nil, err
+		}
+		return Gauge32(u), nil
+	case TypeTimeTicks:
+		return convertTimeTicks(t, v)
+	case TypeOpaque:
+		octets, err := convertOctets(t, v)
+		if err != nil {
+			return nil, err
+		}
+		if len(octets) > MaxOctetStringLength {
+			return nil, conversionError(t, v, "too long")
+		}
+		return Opaque(octets), nil
+	case TypeCounter64:
+		u, err := convertUnsigned(t, v, math.MaxUint64)
+		if err != nil {
+			return nil, err
+		}
+		return Counter64(u), nil
+	}
+	return nil, conversionError(t, v, "unsupported type")
+}
+
+func convertSigned(t Type, v any, min, max int64) (int64, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i := rv.Int(); i >= min && i <= max {
+			return i, nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := rv.Uint(); u <= uint64(max) {
+			return int64(u), nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if f := math.Round(rv.Float()); f >= float64(min) && f <= float64(max) {
+			return int64(f), nil
+		}
+	case reflect.Bool:
+		return 0, conversionError(t, v, "ambiguous bool, use tc.TruthValue")
+	default:
+		return 0, conversionError(t, v, "unsupported value")
+	}
+	return 0, conversionError(t, v, "out of range")
+}
+
+func convertUnsigned(t Type, v any, max uint64) (uint64, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i := rv.Int(); i >= 0 && uint64(i) <= max {
+			return uint64(i), nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := rv.Uint(); u <= max {
+			return u, nil
+		}
+	case reflect.Float32, reflect.Float64:
+		// float64(max) might be rounded up, so the upper bound is exclusive
+		if f := math.Round(rv.Float()); f >= 0 && f < float64(max) {
+			return uint64(f), nil
+		}
+	case reflect.Bool:
+		if rv.Bool() {
+			return 1, nil
+		}
+		return 0, nil
+	default:
+		return 0, conversionError(t, v, "unsupported value")
+	}
+	return 0, conversionError(t, v, "out of range")
+}
+
+func convertTimeTicks(t Type, v any) (Value, error) {
+	switch vv := v.(type) {
+	case time.Duration:
+		return convertTimeTicks(t, float64(vv)/float64(time.Second/100))
+	case time.Time:
+		return convertTimeTicks(t, time.Since(vv))
+	}
+	u, err := convertUnsigned(t, v, math.MaxUint32)
+	if err != nil {
+		return nil, err
+	}
+	return TimeTicks(u), nil
+}
+
+func convertOctets(t Type, v any) ([]byte, error) {
+	switch vv := v.(type) {
+	case []byte:
+		return vv, nil
+	case string:
+		return []byte(vv), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.Kind() == reflect.String:
+		return []byte(rv.String()), nil
+	case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+		return rv.Bytes(), nil
+	}
+
+	if stringer, ok := v.(fmt.Stringer); ok {
+		return []byte(stringer.String()), nil
+	}
+	return nil, conversionError(t, v, "unsupported value")
+}
+
+func convertOID(t Type, v any) (Value, error) {
+	switch vv := v.(type) {
+	case []uint32:
+		return OID(vv), nil
+	case string:
+		oid, err := ParseOID(vv)
+		if err != nil {
+			return nil, conversionError(t, v, err.Error())
+		}
+		return oid, nil
+	}
+	return nil, conversionError(t, v, "unsupported value")
+}
+
+func convertIPAddress(t Type, v any) (Value, error) {
+	switch vv := v.(type) {
+	case [4]byte:
+		return IPAddress(vv), nil
+	case net.IP:
+		if ip4 := vv.To4(); ip4 != nil {
+			return IPAddress(ip4), nil
+		}
+		return nil, conversionError(t, v, "not an IPv4 address")
+	case netip.Addr:
+		if addr := vv.Unmap(); addr.Is4() {
+			return IPAddress(addr.As4()), nil
+		}
+		return nil, conversionError(t, v, "not an IPv4 address")
+	case string:
+		addr, err := netip.ParseAddr(vv)
+		if err != nil {
+			return nil, conversionError(t, v, err.Error())
+		}
+		return convertIPAddress(t, addr)
+	}
+	return nil, conversionError(t, v, "unsupported value")
+}
+
+func conversionError(t Type, v any, reason string) error {
+	return fmt.Errorf("%w: %v (%T) to %s: %s", ErrConversion, v, v, t, reason)
+}
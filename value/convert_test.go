@@ -0,0 +1,102 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package value_test
+
+import (
+	"math"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Olian04/go-agentx/value"
+)
+
+type level int
+
+type name string
+
+func (n name) String() string { return string(n) }
+
+type stringer struct{}
+
+func (stringer) String() string { return "stringer" }
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		typ      value.Type
+		value    any
+		expected value.Value
+	}{
+		{"int to Integer32", value.TypeInteger, -5, value.Integer32(-5)},
+		{"uint8 to Integer32", value.TypeInteger, uint8(5), value.Integer32(5)},
+		{"named int to Integer32", value.TypeInteger, level(3), value.Integer32(3)},
+		{"float to Integer32", value.TypeInteger, 2.6, value.Integer32(3)},
+		{"true to Gauge32", value.TypeGauge32, true, value.Gauge32(1)},
+		{"false to Counter64", value.TypeCounter64, false, value.Counter64(0)},
+		{"int64 to Counter32", value.TypeCounter32, int64(math.MaxUint32), value.Counter32(math.MaxUint32)},
+		{"uint to Gauge32", value.TypeGauge32, uint(7), value.Gauge32(7)},
+		{"Counter32 to Gauge32", value.TypeGauge32, value.Counter32(7), value.Gauge32(7)},
+		{"float to Gauge32", value.TypeGauge32, 0.4, value.Gauge32(0)},
+		{"uint64 to Counter64", value.TypeCounter64, uint64(math.MaxUint64), value.Counter64(math.MaxUint64)},
+		{"duration to TimeTicks", value.TypeTimeTicks, 2 * time.Second, value.TimeTicks(200)},
+		{"int to TimeTicks", value.TypeTimeTicks, 200, value.TimeTicks(200)},
+		{"string to OctetString", value.TypeOctetString, "test", value.OctetString("test")},
+		{"bytes to OctetString", value.TypeOctetString, []byte{0, 1}, value.OctetString{0, 1}},
+		{"hardware address to OctetString", value.TypeOctetString, net.HardwareAddr{1, 2, 3, 4, 5, 6}, value.OctetString{1, 2, 3, 4, 5, 6}},
+		{"named string to OctetString", value.TypeOctetString, name("eth0"), value.OctetString("eth0")},
+		{"stringer to OctetString", value.TypeOctetString, stringer{}, value.OctetString("stringer")},
+		{"bytes to Opaque", value.TypeOpaque, []byte{1}, value.Opaque{1}},
+		{"string to OID", value.TypeObjectIdentifier, "1.3.6.1", value.OID{1, 3, 6, 1}},
+		{"subidentifiers to OID", value.TypeObjectIdentifier, []uint32{1, 3}, value.OID{1, 3}},
+		{"IPv4 to IPAddress", value.TypeIPAddress, net.IPv4(10, 0, 0, 1), value.IPAddress{10, 0, 0, 1}},
+		{"netip to IPAddress", value.TypeIPAddress, netip.MustParseAddr("10.0.0.2"), value.IPAddress{10, 0, 0, 2}},
+		{"string to IPAddress", value.TypeIPAddress, "10.0.0.3", value.IPAddress{10, 0, 0, 3}},
+		{"typed value", value.TypeCounter64, value.Counter64(1), value.Counter64(1)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := value.Convert(test.typ, test.value)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		typ   value.Type
+		value any
+	}{
+		{"int64 overflows Integer32", value.TypeInteger, int64(math.MaxInt32 + 1)},
+		{"negative Counter32", value.TypeCounter32, -1},
+		{"uint64 overflows Gauge32", value.TypeGauge32, uint64(math.MaxUint32 + 1)},
+		{"NaN to Gauge32", value.TypeGauge32, math.NaN()},
+		{"float overflows Counter64", value.TypeCounter64, 1e20},
+		{"negative duration to TimeTicks", value.TypeTimeTicks, -time.Second},
+		{"string to Integer32", value.TypeInteger, "5"},
+		{"bool to Integer32", value.TypeInteger, true},
+		{"int to OctetString", value.TypeOctetString, 5},
+		{"too long Opaque", value.TypeOpaque, make([]byte, value.MaxOctetStringLength+1)},
+		{"invalid OID", value.TypeObjectIdentifier, "1.x"},
+		{"IPv6 to IPAddress", value.TypeIPAddress, net.ParseIP("::1")},
+		{"nil", value.TypeInteger, nil},
+		{"unsupported type", value.TypeNull, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.NotPanics(t, func() {
+				converted, err := value.Convert(test.typ, test.value)
+				assert.ErrorIs(t, err, value.ErrConversion)
+				assert.Nil(t, converted)
+			})
+		})
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package value
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// The kinds of index fields.
+const (
+	// IndexInteger defines a non-negative INTEGER / Integer32 index field. It is
+	// encoded as a single subidentifier and decoded as Integer32.
+	IndexInteger IndexKind = iota + 1
+
+	// IndexUnsigned defines an Unsigned32 / Gauge32 index field. It is encoded
+	// as a single subidentifier and decoded as Gauge32.
+	IndexUnsigned
+
+	// IndexOctetString defines an OCTET STRING index field. Each octet is encoded
+	// as a subidentifier. The octets are preceded by their length, unless the
+	// field has a fixed size or is implied. It is decoded as OctetString.
+	IndexOctetString
+
+	// IndexIPAddress defines an IpAddress index field. It is encoded as four
+	// subidentifiers and decoded as IPAddress.
+	IndexIPAddress
+
+	// IndexOID defines an OBJECT IDENTIFIER index field. The subidentifiers are
+	// preceded by their count, unless the field is implied. It is decoded as OID.
+	IndexOID
+)
+
+// ErrInvalidIndex is returned (wrapped), if index values can't be encoded with
+// or an oid suffix can't be decoded with an index schema.
+var ErrInvalidIndex = errors.New("invalid index")
+
+// IndexKind defines the kind of an index field.
+type IndexKind int
+
+func (k IndexKind) String() string {
+	switch k {
+	case IndexInteger:
+		return "Integer"
+	case IndexUnsigned:
+		return "Unsigned"
+	case IndexOctetString:
+		return "OctetString"
+	case IndexIPAddress:
+		return "IPAddress"
+	case IndexOID:
+		return "OID"
+	}
+	return fmt.Sprintf("IndexKindUnknown (%d)", int(k))
+}
+
+// IndexField defines a field of a table index.
+type IndexField struct {
+	Kind IndexKind
+	// Size defines the fixed size of an IndexOctetString field. Zero means variable length.
+	Size int
+	// Implied marks the last field of an index as IMPLIED, so its length isn't encoded.
+	Implied bool
+}
+
+// IndexSchema defines the fields of a table index. Indexes are encoded into
+// and decoded from oid suffixes following RFC 2578, section 7.7. The order of
+// the encoded suffixes (see CompareOIDs) matches the order of the rows in the
+// table.
+type IndexSchema []IndexField
+
+// Validate returns an error, if the schema contains an unknown kind or an
+// implied field, that isn't the last variable length field.
+func (s IndexSchema) Validate() error {
+	for i, field := range s {
+		switch field.Kind {
+		case IndexInteger, IndexUnsigned, IndexIPAddress:
+			if field.Implied || field.Size != 0 {
+				return fmt.Errorf("%w: field %d: %s can't be implied or sized", ErrInvalidIndex, i, field.Kind)
+			}
+		case IndexOctetString, IndexOID:
+			if field.Size < 0 || field.Kind == IndexOID && field.Size != 0 {
+				return fmt.Errorf("%w: field %d: invalid size %d", ErrInvalidIndex, i, field.Size)
+			}
+			if field.Implied && (i != len(s)-1 || field.Size != 0) {
+				return fmt.Errorf("%w: field %d: only the last variable length field can be implied", ErrInvalidIndex, i)
+			}
+		default:
+			return fmt.Errorf("%w: field %d: unknown kind %s", ErrInvalidIndex, i, field.Kind)
+		}
+	}
+	return nil
+}
+
+// Encode returns the oid suffix of the provided index values. There must be
+// one value per field. The values are converted like in Convert.
+func (s IndexSchema) Encode(values ...any) (OID, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	if len(values) != len(s) {
+		return nil, fmt.Errorf("%w: %d values for %d fields", ErrInvalidIndex, len(values), len(s))
+	}
+
+	var result OID
+	for i, field := range s {
+		var err error
+		result, err = field.append(result, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %d: %w", ErrInvalidIndex, i, err)
+		}
+	}
+	if len(result) > MaxSubidentifiers {
+		return nil, fmt.Errorf("%w: %d subidentifiers", ErrInvalidIndex, len(result))
+	}
+	return result, nil
+}
+
+// Decode returns the index values of the provided oid suffix. The suffix must
+// contain exactly the encoded index.
+func (s IndexSchema) Decode(suffix OID) ([]Value, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	result := make([]Value, 0, len(s))
+	for i, field := range s {
+		value, rest, err := field.decode(suffix)
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %d: %w", ErrInvalidIndex, i, err)
+		}
+		result = append(result, value)
+		suffix = rest
+	}
+	if len(suffix) > 0 {
+		return nil, fmt.Errorf("%w: %d trailing subidentifiers", ErrInvalidIndex, len(suffix))
+	}
+	return result, nil
+}
+
+func (f IndexField) append(oid OID, v any) (OID, error) {
+	switch f.Kind {
+	case IndexInteger:
+		i, err := convertSigned(TypeInteger, v, 0, math.MaxInt32)
+		if err != nil {
+			return nil, err
+		}
+		return append(oid, uint32(i)), nil
+	case IndexUnsigned:
+		u, err := convertUnsigned(TypeGauge32, v, math.MaxUint32)
+		if err != nil {
+			return nil, err
+		}
+		return append(oid, uint32(u)), nil
+	case IndexOctetString:
+		octets, err := convertOctets(TypeOctetString, v)
+		if err != nil {
+			return nil, err
+		}
+		if f.Size > 0 && len(octets) != f.Size {
+			return nil, fmt.Errorf("%d octets for a fixed size of %d", len(octets), f.Size)
+		}
+		if f.Size == 0 && !f.Implied {
+			oid = append(oid, uint32(len(octets)))
+		}
+		for _, octet := range octets {
+			oid = append(oid, uint32(octet))
+		}
+		return oid, nil
+	case IndexIPAddress:
+		ip, err := Convert(TypeIPAddress, v)
+		if err != nil {
+			return nil, err
+		}
+		for _, octet := range ip.(IPAddress) {
+			oid = append(oid, uint32(octet))
+		}
+		return oid, nil
+	case IndexOID:
+		converted, err := Convert(TypeObjectIdentifier, v)
+		if err != nil {
+			return nil, err
+		}
+		subidentifiers := converted.(OID)
+		if !f.Implied {
+			oid = append(oid, uint32(len(subidentifiers)))
+		}
+		return append(oid, subidentifiers...), nil
+	}
+	return nil, fmt.Errorf("unknown kind %s", f.Kind)
+}
+
+func (f IndexField) decode(suffix OID) (Value, OID, error) {
+	switch f.Kind {
+	case IndexInteger:
+		if len(suffix) < 1 {
+			return nil, nil, errors.New("missing subidentifier")
+		}
+		if suffix[0] > math.MaxInt32 {
+			return nil, nil, fmt.Errorf("integer %d out of range", suffix[0])
+		}
+		return Integer32(suffix[0]), suffix[1:], nil
+	case IndexUnsigned:
+		if len(suffix) < 1 {
+			return nil, nil, errors.New("missing subidentifier")
+		}
+		return Gauge32(suffix[0]), suffix[1:], nil
+	case IndexOctetString:
+		length, rest, err := f.length(suffix)
+		if err != nil {
+			return nil, nil, err
+		}
+		octets := make(OctetString, length)
+		for i, subidentifier := range rest[:length] {
+			if subidentifier > math.MaxUint8 {
+				return nil, nil, fmt.Errorf("octet %d out of range", subidentifier)
+			}
+			octets[i] = byte(subidentifier)
+		}
+		return octets, rest[length:], nil
+	case IndexIPAddress:
+		if len(suffix) < 4 {
+			return nil, nil, fmt.Errorf("%d subidentifiers for an ip address", len(suffix))
+		}
+		var ip IPAddress
+		for i, subidentifier := range suffix[:4] {
+			if subidentifier > math.MaxUint8 {
+				return nil, nil, fmt.Errorf("octet %d out of range", subidentifier)
+			}
+			ip[i] = byte(subidentifier)
+		}
+		return ip, suffix[4:], nil
+	case IndexOID:
+		length, rest, err := f.length(suffix)
+		if err != nil {
+			return nil, nil, err
+		}
+		return rest[:length].Clone(), rest[length:], nil
+	}
+	return nil, nil, fmt.Errorf("unknown kind %s", f.Kind)
+}
+
+// length returns the number of subidentifiers of a variable length field and
+// the suffix without the length prefix.
+func (f IndexField) length(suffix OID) (int, OID, error) {
+	switch {
+	case f.Implied:
+		return len(suffix), suffix, nil
+	case f.Size > 0:
+		if len(suffix) < f.Size {
+			return 0, nil, fmt.Errorf("%d subidentifiers for a fixed size of %d", len(suffix), f.Size)
+		}
+		return f.Size, suffix, nil
+	}
+	if len(suffix) < 1 {
+		return 0, nil, errors.New("missing length")
+	}
+	if uint64(suffix[0]) > uint64(len(suffix)-1) {
+		return 0, nil, fmt.Errorf("length %d exceeds %d subidentifiers", suffix[0], len(suffix)-1)
+	}
+	return int(suffix[0]), suffix[1:], nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package value_test
+
+import (
+	"net/netip"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Olian04/go-agentx/value"
+)
+
+func TestIndexSchemaEncode(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   value.IndexSchema
+		values   []any
+		expected value.OID
+	}{
+		{"integer", value.IndexSchema{{Kind: value.IndexInteger}}, []any{5}, value.OID{5}},
+		{"unsigned", value.IndexSchema{{Kind: value.IndexUnsigned}}, []any{uint32(4000000000)}, value.OID{4000000000}},
+		{"octet string", value.IndexSchema{{Kind: value.IndexOctetString}}, []any{"ab"}, value.OID{2, 'a', 'b'}},
+		{"fixed size octet string", value.IndexSchema{{Kind: value.IndexOctetString, Size: 2}}, []any{[]byte{1, 2}}, value.OID{1, 2}},
+		{"implied octet string", value.IndexSchema{{Kind: value.IndexOctetString, Implied: true}}, []any{"ab"}, value.OID{'a', 'b'}},
+		{"ip address", value.IndexSchema{{Kind: value.IndexIPAddress}}, []any{netip.MustParseAddr("10.0.0.1")}, value.OID{10, 0, 0, 1}},
+		{"oid", value.IndexSchema{{Kind: value.IndexOID}}, []any{value.OID{1, 3}}, value.OID{2, 1, 3}},
+		{"implied oid", value.IndexSchema{{Kind: value.IndexOID, Implied: true}}, []any{"1.3"}, value.OID{1, 3}},
+		{"composite", value.IndexSchema{{Kind: value.IndexInteger}, {Kind: value.IndexOctetString}, {Kind: value.IndexIPAddress}},
+			[]any{value.Integer32(1), value.DisplayString("x"), value.IPAddress{127, 0, 0, 1}}, value.OID{1, 1, 'x', 127, 0, 0, 1}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			oid, err := test.schema.Encode(test.values...)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, oid)
+
+			values, err := test.schema.Decode(oid)
+			require.NoError(t, err)
+			require.Len(t, values, len(test.values))
+			reencoded := make([]any, len(values))
+			for i, v := range values {
+				reencoded[i] = v
+			}
+			oid, err = test.schema.Encode(reencoded...)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, oid)
+		})
+	}
+}
+
+func TestIndexSchemaDecodeTypes(t *testing.T) {
+	schema := value.IndexSchema{
+		{Kind: value.IndexInteger},
+		{Kind: value.IndexUnsigned},
+		{Kind: value.IndexIPAddress},
+		{Kind: value.IndexOctetString, Implied: true},
+	}
+	values, err := schema.Decode(value.OID{1, 2, 10, 0, 0, 1, 'a'})
+	require.NoError(t, err)
+	assert.Equal(t, []value.Value{value.Integer32(1), value.Gauge32(2), value.IPAddress{10, 0, 0, 1}, value.OctetString("a")}, values)
+}
+
+func TestIndexSchemaInvalid(t *testing.T) {
+	_, err := value.IndexSchema{{Kind: value.IndexOctetString, Implied: true}, {Kind: value.IndexInteger}}.Encode("a", 1)
+	assert.ErrorIs(t, err, value.ErrInvalidIndex)
+	_, err = value.IndexSchema{{Kind: value.IndexInteger, Implied: true}}.Encode(1)
+	assert.ErrorIs(t, err, value.ErrInvalidIndex)
+	_, err = value.IndexSchema{{}}.Encode(1)
+	assert.ErrorIs(t, err, value.ErrInvalidIndex)
+
+	schema := value.IndexSchema{{Kind: value.IndexInteger}, {Kind: value.IndexOctetString}}
+	for _, values := range [][]any{{1}, {-1, "a"}, {true, "a"}, {1, 2.5}} {
+		_, err = schema.Encode(values...)
+		assert.ErrorIs(t, err, value.ErrInvalidIndex, "%v", values)
+	}
+	_, err = value.IndexSchema{{Kind: value.IndexIPAddress}}.Encode("::1")
+	assert.ErrorIs(t, err, value.ErrInvalidIndex)
+	_, err = value.IndexSchema{{Kind: value.IndexOctetString, Size: 2}}.Encode("abc")
+	assert.ErrorIs(t, err, value.ErrInvalidIndex)
+
+	for _, suffix := range []value.OID{{}, {1}, {1, 3, 'a'}, {1, 1, 256}, {1, 1, 'a', 2}, {1 << 31, 0}} {
+		_, err = schema.Decode(suffix)
+		assert.ErrorIs(t, err, value.ErrInvalidIndex, "%v", suffix)
+	}
+}
+
+func TestIndexSchemaOrdering(t *testing.T) {
+	schema := value.IndexSchema{{Kind: value.IndexOctetString}, {Kind: value.IndexInteger}}
+	rows := [][]any{{"b", 1}, {"ab", 2}, {"a", 3}, {"b", 0}, {"", 7}}
+	oids := make([]value.OID, 0, len(rows))
+	for _, row := range rows {
+		oid, err := schema.Encode(row...)
+		require.NoError(t, err)
+		oids = append(oids, oid)
+	}
+	value.SortOIDs(oids)
+
+	var sorted []string
+	for _, oid := range oids {
+		values, err := schema.Decode(oid)
+		require.NoError(t, err)
+		sorted = append(sorted, string(values[0].(value.OctetString)))
+	}
+	// shorter strings sort first, as the length is encoded first
+	assert.True(t, slices.Equal([]string{"", "a", "b", "b", "ab"}, sorted), "%v", sorted)
+}
@@ -0,0 +1,137 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package value
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+)
+
+// The address types of the InetAddressType textual convention (RFC 4001).
+const (
+	InetAddressTypeUnknown InetAddressType = 0
+	InetAddressTypeIPv4    InetAddressType = 1
+	InetAddressTypeIPv6    InetAddressType = 2
+	InetAddressTypeIPv4z   InetAddressType = 3
+	InetAddressTypeIPv6z   InetAddressType = 4
+	InetAddressTypeDNS     InetAddressType = 16
+)
+
+// ErrInvalidInetAddress is returned (wrapped), if an InetAddress doesn't match
+// its InetAddressType.
+var ErrInvalidInetAddress = errors.New("invalid inet address")
+
+// InetAddressType defines an InetAddressType value (RFC 4001). It is encoded
+// as an Integer32.
+type InetAddressType int32
+
+// Type returns TypeInteger.
+func (t InetAddressType) Type() Type { return TypeInteger }
+
+// ByteSize returns the number of bytes, the value would need in the encoded version.
+func (t InetAddressType) ByteSize() int { return 4 }
+
+// AppendBinary appends the encoded value to b and returns the extended slice.
+func (t InetAddressType) AppendBinary(b []byte) ([]byte, error) {
+	return binary.LittleEndian.AppendUint32(b, uint32(t)), nil
+}
+
+func (t InetAddressType) String() string {
+	switch t {
+	case InetAddressTypeUnknown:
+		return "unknown"
+	case InetAddressTypeIPv4:
+		return "ipv4"
+	case InetAddressTypeIPv6:
+		return "ipv6"
+	case InetAddressTypeIPv4z:
+		return "ipv4z"
+	case InetAddressTypeIPv6z:
+		return "ipv6z"
+	case InetAddressTypeDNS:
+		return "dns"
+	}
+	return fmt.Sprintf("InetAddressTypeUnknown (%d)", int32(t))
+}
+
+// InetAddress returns the InetAddressType and InetAddress pair for the provided
+// address (RFC 4001). IPv4-mapped IPv6 addresses are returned as IPv4 addresses.
+// A zone must either be numeric or the name of a network interface, whose index
+// is used as zone index. The zero netip.Addr is returned as unknown type with an
+// empty address.
+func InetAddress(addr netip.Addr) (InetAddressType, OctetString, error) {
+	if !addr.IsValid() {
+		return InetAddressTypeUnknown, OctetString{}, nil
+	}
+
+	zone := addr.Zone()
+	addr = addr.Unmap().WithZone("")
+	t := InetAddressTypeIPv6
+	if addr.Is4() {
+		t = InetAddressTypeIPv4
+	}
+	result := OctetString(addr.AsSlice())
+
+	if zone != "" {
+		index, err := zoneIndex(zone)
+		if err != nil {
+			return InetAddressTypeUnknown, nil, fmt.Errorf("%w: zone %q: %w", ErrInvalidInetAddress, zone, err)
+		}
+		t += InetAddressTypeIPv4z - InetAddressTypeIPv4
+		result = binary.BigEndian.AppendUint32(result, index)
+	}
+
+	return t, result, nil
+}
+
+// ParseInetAddress returns the address of the provided InetAddressType and
+// InetAddress pair (RFC 4001). The zone index of ipv4z and ipv6z addresses is
+// set as numeric zone; ipv4z addresses are returned as IPv4-mapped IPv6 addresses,
+// as netip.Addr doesn't support zones for IPv4. The types unknown and dns are not
+// supported.
+func ParseInetAddress(t InetAddressType, b []byte) (netip.Addr, error) {
+	size, zoned := 0, false
+	switch t {
+	case InetAddressTypeIPv4:
+		size = 4
+	case InetAddressTypeIPv6:
+		size = 16
+	case InetAddressTypeIPv4z:
+		size, zoned = 8, true
+	case InetAddressTypeIPv6z:
+		size, zoned = 20, true
+	default:
+		return netip.Addr{}, fmt.Errorf("%w: unsupported type %s", ErrInvalidInetAddress, t)
+	}
+	if len(b) != size {
+		return netip.Addr{}, fmt.Errorf("%w: %s address with %d bytes", ErrInvalidInetAddress, t, len(b))
+	}
+
+	if !zoned {
+		addr, _ := netip.AddrFromSlice(b)
+		return addr, nil
+	}
+	addr, _ := netip.AddrFromSlice(b[:size-4])
+	index := binary.BigEndian.Uint32(b[size-4:])
+	if t == InetAddressTypeIPv4z {
+		addr = netip.AddrFrom16(addr.As16())
+	}
+	return addr.WithZone(strconv.FormatUint(uint64(index), 10)), nil
+}
+
+func zoneIndex(zone string) (uint32, error) {
+	if index, err := strconv.ParseUint(zone, 10, 32); err == nil {
+		return uint32(index), nil
+	}
+	iface, err := net.InterfaceByName(zone)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(iface.Index), nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package value_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Olian04/go-agentx/value"
+)
+
+func TestInetAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		addr     netip.Addr
+		typ      value.InetAddressType
+		expected value.OctetString
+	}{
+		{"invalid", netip.Addr{}, value.InetAddressTypeUnknown, value.OctetString{}},
+		{"ipv4", netip.MustParseAddr("10.0.0.1"), value.InetAddressTypeIPv4, value.OctetString{10, 0, 0, 1}},
+		{"ipv4 mapped", netip.MustParseAddr("::ffff:10.0.0.1"), value.InetAddressTypeIPv4, value.OctetString{10, 0, 0, 1}},
+		{"ipv4z", netip.MustParseAddr("::ffff:10.0.0.1%3"), value.InetAddressTypeIPv4z, value.OctetString{10, 0, 0, 1, 0, 0, 0, 3}},
+		{"ipv6", netip.MustParseAddr("2001:db8::1"), value.InetAddressTypeIPv6,
+			value.OctetString{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}},
+		{"ipv6z", netip.MustParseAddr("fe80::1%2"), value.InetAddressTypeIPv6z,
+			value.OctetString{0xfe, 0x80, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 2}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			typ, addr, err := value.InetAddress(test.addr)
+			require.NoError(t, err)
+			assert.Equal(t, test.typ, typ)
+			assert.Equal(t, test.expected, addr)
+
+			if typ == value.InetAddressTypeUnknown {
+				return
+			}
+			parsed, err := value.ParseInetAddress(typ, addr)
+			require.NoError(t, err)
+			typ, roundTrip, err := value.InetAddress(parsed)
+			require.NoError(t, err)
+			assert.Equal(t, test.typ, typ)
+			assert.Equal(t, test.expected, roundTrip)
+		})
+	}
+}
+
+func TestInetAddressInvalidZone(t *testing.T) {
+	_, _, err := value.InetAddress(netip.MustParseAddr("fe80::1%no-such-interface"))
+	assert.ErrorIs(t, err, value.ErrInvalidInetAddress)
+}
+
+func TestParseInetAddressInvalid(t *testing.T) {
+	_, err := value.ParseInetAddress(value.InetAddressTypeIPv4, []byte{10, 0, 0})
+	assert.ErrorIs(t, err, value.ErrInvalidInetAddress)
+	_, err = value.ParseInetAddress(value.InetAddressTypeDNS, []byte("example.org"))
+	assert.ErrorIs(t, err, value.ErrInvalidInetAddress)
+}
+
+func TestInetAddressType(t *testing.T) {
+	data, err := value.InetAddressTypeIPv6.AppendBinary(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{2, 0, 0, 0}, data)
+	assert.Equal(t, value.TypeInteger, value.InetAddressTypeIPv6.Type())
+	assert.Equal(t, "ipv6z", value.InetAddressTypeIPv6z.String())
+}
@@ -0,0 +1,100 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package value
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var names = struct {
+	sync.RWMutex
+	oids  map[string]OID
+	names map[string]string
+}{
+	oids:  map[string]OID{},
+	names: map[string]string{},
+}
+
+func init() {
+	for name, text := range map[string]string{
+		"iso":          "1",
+		"org":          "1.3",
+		"dod":          "1.3.6",
+		"internet":     "1.3.6.1",
+		"directory":    "1.3.6.1.1",
+		"mgmt":         "1.3.6.1.2",
+		"mib-2":        "1.3.6.1.2.1",
+		"system":       "1.3.6.1.2.1.1",
+		"sysDescr":     "1.3.6.1.2.1.1.1",
+		"sysObjectID":  "1.3.6.1.2.1.1.2",
+		"sysUpTime":    "1.3.6.1.2.1.1.3",
+		"sysContact":   "1.3.6.1.2.1.1.4",
+		"sysName":      "1.3.6.1.2.1.1.5",
+		"sysLocation":  "1.3.6.1.2.1.1.6",
+		"sysServices":  "1.3.6.1.2.1.1.7",
+		"interfaces":   "1.3.6.1.2.1.2",
+		"ifTable":      "1.3.6.1.2.1.2.2",
+		"ip":           "1.3.6.1.2.1.4",
+		"ifMIB":        "1.3.6.1.2.1.31",
+		"experimental": "1.3.6.1.3",
+		"private":      "1.3.6.1.4",
+		"enterprises":  "1.3.6.1.4.1",
+		"security":     "1.3.6.1.5",
+		"snmpV2":       "1.3.6.1.6",
+		"snmpModules":  "1.3.6.1.6.3",
+	} {
+		if err := RegisterName(name, MustParseOID(text)); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterName registers the provided name for the oid. The name can be used
+// as first component in ParseOID and is used by NamedString. Registering an
+// existing name again replaces its oid. A name must not be empty, must not
+// start with a digit and must not contain a dot.
+func RegisterName(name string, oid OID) error {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') || strings.ContainsRune(name, '.') {
+		return fmt.Errorf("invalid oid name %q", name)
+	}
+
+	names.Lock()
+	defer names.Unlock()
+	if previous, ok := names.oids[name]; ok && names.names[previous.String()] == name {
+		delete(names.names, previous.String())
+	}
+	names.oids[name] = oid.Clone()
+	names.names[oid.String()] = name
+	return nil
+}
+
+// LookupName returns the oid, that is registered for the provided name.
+func LookupName(name string) (OID, bool) {
+	names.RLock()
+	defer names.RUnlock()
+	oid, ok := names.oids[name]
+	return oid.Clone(), ok
+}
+
+// NamedString returns the oid in dotted notation, where the longest prefix with
+// a registered name is replaced by that name (e.g. "enterprises.45995.3.1").
+// If no prefix has a registered name, the numeric notation is returned.
+func (o OID) NamedString() string {
+	names.RLock()
+	defer names.RUnlock()
+	for i := len(o); i > 0; i-- {
+		name, ok := names.names[o[:i].String()]
+		if !ok {
+			continue
+		}
+		if i == len(o) {
+			return name
+		}
+		return name + "." + o[i:].String()
+	}
+	return o.String()
+}
@@ -0,0 +1,56 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package value_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Olian04/go-agentx/value"
+)
+
+func TestParseOIDLeadingDot(t *testing.T) {
+	oid, err := value.ParseOID(".1.3.6.1.4.1.45995")
+	require.NoError(t, err)
+	assert.Equal(t, value.OID{1, 3, 6, 1, 4, 1, 45995}, oid)
+}
+
+func TestParseOIDNames(t *testing.T) {
+	oid, err := value.ParseOID("enterprises.45995.3.1")
+	require.NoError(t, err)
+	assert.Equal(t, value.OID{1, 3, 6, 1, 4, 1, 45995, 3, 1}, oid)
+
+	oid, err = value.ParseOID("sysDescr")
+	require.NoError(t, err)
+	assert.Equal(t, value.OID{1, 3, 6, 1, 2, 1, 1, 1}, oid)
+
+	_, err = value.ParseOID("noSuchName.1")
+	assert.ErrorIs(t, err, value.ErrInvalidOID)
+	_, err = value.ParseOID("enterprises.x")
+	assert.Error(t, err)
+}
+
+func TestRegisterName(t *testing.T) {
+	require.NoError(t, value.RegisterName("testAgent", value.OID{1, 3, 6, 1, 4, 1, 45995, 3}))
+
+	oid, err := value.ParseOID("testAgent.1")
+	require.NoError(t, err)
+	assert.Equal(t, value.OID{1, 3, 6, 1, 4, 1, 45995, 3, 1}, oid)
+	assert.Equal(t, "testAgent.1", oid.NamedString())
+	assert.Equal(t, "testAgent", oid.Parent().NamedString())
+
+	assert.Error(t, value.RegisterName("", value.OID{1}))
+	assert.Error(t, value.RegisterName("1abc", value.OID{1}))
+	assert.Error(t, value.RegisterName("a.b", value.OID{1}))
+}
+
+func TestNamedString(t *testing.T) {
+	assert.Equal(t, "enterprises.9999.1", value.OID{1, 3, 6, 1, 4, 1, 9999, 1}.NamedString())
+	assert.Equal(t, "sysUpTime.0", value.OID{1, 3, 6, 1, 2, 1, 1, 3, 0}.NamedString())
+	assert.Equal(t, "2.5.4", value.OID{2, 5, 4}.NamedString())
+	assert.Equal(t, "", value.OID{}.NamedString())
+}
@@ -5,60 +5,89 @@
 package value
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 )
 
+// MaxSubidentifiers defines the maximum number of subidentifiers in an oid (RFC 2578).
+const MaxSubidentifiers = 128
+
+// ErrInvalidOID is returned (wrapped) by Validate and UnmarshalText, if an oid is invalid.
+var ErrInvalidOID = errors.New("invalid oid")
+
 // OID defines an OID.
 type OID []uint32
 
 // ParseOID parses the provided string and returns a valid oid. If one of the
 // subidentifiers cannot be parsed to an uint32, the function will return an error.
+// A leading dot is accepted (".1.3.6.1"). The first component can be a name,
+// that was registered with RegisterName (e.g. "enterprises.45995.3.1").
 func ParseOID(text string) (OID, error) {
+	text = strings.TrimPrefix(text, ".")
+	if len(text) > 0 && (text[0] < '0' || text[0] > '9') {
+		name, rest, _ := strings.Cut(text, ".")
+		prefix, ok := LookupName(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown name %q", ErrInvalidOID, name)
+		}
+		if rest == "" {
+			return prefix, nil
+		}
+		suffix, err := parseNumericOID(rest)
+		if err != nil {
+			return nil, err
+		}
+		return prefix.Append(suffix...), nil
+	}
+	return parseNumericOID(text)
+}
+
+func parseNumericOID(text string) (OID, error) {
 	result := make(OID, 0, 8) // default small capacity
-	var current uint32
+	var current uint64
 	haveDigit := false
 	for i := 0; i < len(text); i++ {
 		ch := text[i]
 		if ch >= '0' && ch <= '9' {
 			haveDigit = true
-			current = current*10 + uint32(ch-'0')
+			current = current*10 + uint64(ch-'0')
+			if current > math.MaxUint32 {
+				// overflow, fall back to strconv for the error
+				return parseNumericOIDStrict(text)
+			}
 			continue
 		}
-		if ch == '.' {
-			if !haveDigit {
-				// invalid empty component, fall back to strconv for error consistency
-				parts := strings.Split(text, ".")
-				result = result[:0]
-				for _, part := range parts {
-					val, err := strconv.ParseUint(part, 10, 32)
-					if err != nil {
-						return nil, err
-					}
-					result = append(result, uint32(val))
-				}
-				return result, nil
-			}
-			result = append(result, current)
+		if ch == '.' && haveDigit {
+			result = append(result, uint32(current))
 			current = 0
 			haveDigit = false
 			continue
 		}
-		// invalid char, delegate to slower parser for error
-		parts := strings.Split(text, ".")
-		result = result[:0]
-		for _, part := range parts {
-			val, err := strconv.ParseUint(part, 10, 32)
-			if err != nil {
-				return nil, err
-			}
-			result = append(result, uint32(val))
-		}
-		return result, nil
+		// invalid char or empty component, fall back to strconv for the error
+		return parseNumericOIDStrict(text)
 	}
 	if haveDigit {
-		result = append(result, current)
+		result = append(result, uint32(current))
+	}
+	return result, nil
+}
+
+// parseNumericOIDStrict parses each subidentifier with strconv, which reports
+// invalid characters, empty subidentifiers and subidentifiers out of range.
+func parseNumericOIDStrict(text string) (OID, error) {
+	parts := strings.Split(text, ".")
+	result := make(OID, 0, len(parts))
+	for _, part := range parts {
+		val, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidOID, err)
+		}
+		result = append(result, uint32(val))
 	}
 	return result, nil
 }
@@ -92,6 +121,87 @@ func (o OID) CommonPrefix(other OID) OID {
 	return o[:matchCount]
 }
 
+// HasPrefix returns true, if the oid starts with the provided prefix.
+func (o OID) HasPrefix(prefix OID) bool {
+	return len(o) >= len(prefix) && slices.Equal(o[:len(prefix)], prefix)
+}
+
+// IsChildOf returns true, if the oid is located in the subtree below the
+// provided parent. An oid is not a child of itself.
+func (o OID) IsChildOf(parent OID) bool {
+	return len(o) > len(parent) && o.HasPrefix(parent)
+}
+
+// Parent returns the oid without its last subidentifier. The parent of an empty
+// oid is nil.
+func (o OID) Parent() OID {
+	if len(o) == 0 {
+		return nil
+	}
+	return o[: len(o)-1 : len(o)-1]
+}
+
+// Append returns a new oid with the provided subidentifiers appended. The oid
+// itself is never modified.
+func (o OID) Append(subidentifiers ...uint32) OID {
+	result := make(OID, 0, len(o)+len(subidentifiers))
+	result = append(result, o...)
+	return append(result, subidentifiers...)
+}
+
+// Equal returns true, if the oid equals the provided one.
+func (o OID) Equal(other OID) bool {
+	return slices.Equal(o, other)
+}
+
+// Clone returns a copy of the oid.
+func (o OID) Clone() OID {
+	return slices.Clone(o)
+}
+
+// Compare returns an integer comparing the oid with the provided one
+// lexicographically. The result will be 0 if o == other, -1 if o < other and
+// +1 if o > other. Unlike CompareOIDs, a nil oid equals an empty one.
+func (o OID) Compare(other OID) int {
+	return slices.Compare(o, other)
+}
+
+// Validate returns an error, if the oid has more than MaxSubidentifiers
+// subidentifiers or its first arcs are out of range (the first arc must be
+// 0, 1 or 2, the second one below 40 for the first arcs 0 and 1).
+func (o OID) Validate() error {
+	if len(o) > MaxSubidentifiers {
+		return fmt.Errorf("%w: %d subidentifiers, at most %d are allowed", ErrInvalidOID, len(o), MaxSubidentifiers)
+	}
+	if len(o) > 0 && o[0] > 2 {
+		return fmt.Errorf("%w: first arc %d out of range", ErrInvalidOID, o[0])
+	}
+	if len(o) > 1 && o[0] < 2 && o[1] >= 40 {
+		return fmt.Errorf("%w: second arc %d out of range", ErrInvalidOID, o[1])
+	}
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler. It returns the dotted notation
+// of the oid.
+func (o OID) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It parses and validates the
+// dotted notation of an oid.
+func (o *OID) UnmarshalText(text []byte) error {
+	oid, err := ParseOID(string(text))
+	if err != nil {
+		return err
+	}
+	if err := oid.Validate(); err != nil {
+		return err
+	}
+	*o = oid
+	return nil
+}
+
 // CompareOIDs returns an integer comparing two OIDs lexicographically.
 // The result will be 0 if oid1 == oid2, -1 if oid1 < oid2, +1 if oid1 > oid2.
 func CompareOIDs(oid1, oid2 OID) int {
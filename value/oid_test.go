@@ -5,9 +5,12 @@
 package value_test
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/Olian04/go-agentx/value"
 )
@@ -68,3 +71,88 @@ func TestSortOIDs(t *testing.T) {
 	expect = append(expect, oid1, oid3, oid4, oid2)
 	assert.Equal(t, expect, oidList)
 }
+
+func TestOIDPrefixes(t *testing.T) {
+	oid := value.OID{1, 3, 6, 1, 4}
+	assert.True(t, oid.HasPrefix(value.OID{1, 3, 6}))
+	assert.True(t, oid.HasPrefix(oid))
+	assert.True(t, oid.HasPrefix(nil))
+	assert.False(t, oid.HasPrefix(value.OID{1, 3, 7}))
+	assert.False(t, oid.HasPrefix(value.OID{1, 3, 6, 1, 4, 1}))
+
+	assert.True(t, oid.IsChildOf(value.OID{1, 3, 6}))
+	assert.False(t, oid.IsChildOf(oid))
+	assert.False(t, oid.IsChildOf(value.OID{1, 4}))
+}
+
+func TestOIDParentAndAppend(t *testing.T) {
+	oid := value.OID{1, 3, 6, 1}
+	parent := oid.Parent()
+	assert.Equal(t, value.OID{1, 3, 6}, parent)
+	assert.Nil(t, value.OID{}.Parent())
+
+	child := parent.Append(2, 1)
+	assert.Equal(t, value.OID{1, 3, 6, 2, 1}, child)
+	assert.Equal(t, value.OID{1, 3, 6, 1}, oid, "append must not modify the original oid")
+}
+
+func TestOIDEqualCloneCompare(t *testing.T) {
+	oid := value.OID{1, 3, 6, 1}
+	clone := oid.Clone()
+	assert.True(t, oid.Equal(clone))
+	clone[3] = 2
+	assert.False(t, oid.Equal(clone))
+
+	assert.Equal(t, -1, oid.Compare(clone))
+	assert.Equal(t, 1, clone.Compare(oid))
+	assert.Equal(t, 0, oid.Compare(value.OID{1, 3, 6, 1}))
+	assert.Equal(t, 1, oid.Compare(value.OID{1, 3, 6}))
+	assert.Equal(t, 0, value.OID(nil).Compare(value.OID{}))
+}
+
+func TestOIDValidate(t *testing.T) {
+	assert.NoError(t, value.OID{}.Validate())
+	assert.NoError(t, value.OID{1, 3, 6, 1}.Validate())
+	assert.NoError(t, value.OID{2, 999}.Validate())
+	assert.NoError(t, make(value.OID, value.MaxSubidentifiers).Validate())
+
+	assert.ErrorIs(t, value.OID{3, 1}.Validate(), value.ErrInvalidOID)
+	assert.ErrorIs(t, value.OID{1, 40}.Validate(), value.ErrInvalidOID)
+	assert.ErrorIs(t, make(value.OID, value.MaxSubidentifiers+1).Validate(), value.ErrInvalidOID)
+}
+
+func TestOIDTextMarshaling(t *testing.T) {
+	type config struct {
+		Base value.OID `json:"base"`
+	}
+
+	data, err := json.Marshal(config{Base: value.OID{1, 3, 6, 1, 4, 1, 45995}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"base":"1.3.6.1.4.1.45995"}`, string(data))
+
+	var decoded config
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, value.OID{1, 3, 6, 1, 4, 1, 45995}, decoded.Base)
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"base":"1.3.x"}`), &decoded), value.ErrInvalidOID)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"base":"5.3"}`), &decoded), value.ErrInvalidOID)
+
+	// the error is wrapped once
+	var oid value.OID
+	err = oid.UnmarshalText([]byte("1.3.x"))
+	require.ErrorIs(t, err, value.ErrInvalidOID)
+	assert.Equal(t, 1, strings.Count(err.Error(), value.ErrInvalidOID.Error()))
+}
+
+func TestParseOIDOverflow(t *testing.T) {
+	oid, err := value.ParseOID("1.3.4294967295")
+	require.NoError(t, err)
+	assert.Equal(t, value.OID{1, 3, 4294967295}, oid)
+
+	for _, text := range []string{"1.3.4294967296", "1.3.99999999999999999999", "1..3", "1.3.x"} {
+		_, err := value.ParseOID(text)
+		assert.ErrorIs(t, err, value.ErrInvalidOID, text)
+	}
+	var decoded value.OID
+	assert.ErrorIs(t, decoded.UnmarshalText([]byte("1.3.4294967296")), value.ErrInvalidOID)
+}
@@ -0,0 +1,182 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package value
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/Olian04/go-agentx/internal/wire"
+)
+
+// The ASN.1 application tags of the opaque wrapped types (as used by Net-SNMP).
+// They follow the extension tag byte 0x9f.
+const (
+	opaqueTagExtension = 0x9f
+	opaqueTagCounter64 = 0x76
+	opaqueTagFloat     = 0x78
+	opaqueTagDouble    = 0x79
+	opaqueTagInt64     = 0x7a
+	opaqueTagUint64    = 0x7b
+)
+
+// ErrInvalidOpaque is returned (wrapped) by ParseOpaque, if the opaque doesn't
+// contain a supported wrapped value.
+var ErrInvalidOpaque = errors.New("invalid opaque")
+
+// OpaqueFloat defines a float value, that is wrapped into an Opaque (tag 0x9f78).
+type OpaqueFloat float32
+
+// Type returns TypeOpaque.
+func (f OpaqueFloat) Type() Type { return TypeOpaque }
+
+// ByteSize returns the number of bytes, the value would need in the encoded version.
+func (f OpaqueFloat) ByteSize() int { return wire.OctetsSize(3 + 4) }
+
+// AppendBinary appends the encoded value to b and returns the extended slice.
+func (f OpaqueFloat) AppendBinary(b []byte) ([]byte, error) {
+	var data [4]byte
+	binary.BigEndian.PutUint32(data[:], math.Float32bits(float32(f)))
+	return appendOpaque(b, opaqueTagFloat, data[:]), nil
+}
+
+// OpaqueDouble defines a double value, that is wrapped into an Opaque (tag 0x9f79).
+type OpaqueDouble float64
+
+// Type returns TypeOpaque.
+func (d OpaqueDouble) Type() Type { return TypeOpaque }
+
+// ByteSize returns the number of bytes, the value would need in the encoded version.
+func (d OpaqueDouble) ByteSize() int { return wire.OctetsSize(3 + 8) }
+
+// AppendBinary appends the encoded value to b and returns the extended slice.
+func (d OpaqueDouble) AppendBinary(b []byte) ([]byte, error) {
+	var data [8]byte
+	binary.BigEndian.PutUint64(data[:], math.Float64bits(float64(d)))
+	return appendOpaque(b, opaqueTagDouble, data[:]), nil
+}
+
+// OpaqueInt64 defines a signed 64 bit value, that is wrapped into an Opaque (tag 0x9f7a).
+type OpaqueInt64 int64
+
+// Type returns TypeOpaque.
+func (i OpaqueInt64) Type() Type { return TypeOpaque }
+
+// ByteSize returns the number of bytes, the value would need in the encoded version.
+func (i OpaqueInt64) ByteSize() int {
+	var data [8]byte
+	return wire.OctetsSize(3 + len(i.content(&data)))
+}
+
+// AppendBinary appends the encoded value to b and returns the extended slice.
+func (i OpaqueInt64) AppendBinary(b []byte) ([]byte, error) {
+	var data [8]byte
+	return appendOpaque(b, opaqueTagInt64, i.content(&data)), nil
+}
+
+// content returns the minimal two's complement encoding of the value.
+func (i OpaqueInt64) content(data *[8]byte) []byte {
+	binary.BigEndian.PutUint64(data[:], uint64(i))
+	content := data[:]
+	for len(content) > 1 {
+		if content[0] == 0x00 && content[1]&0x80 == 0 || content[0] == 0xff && content[1]&0x80 != 0 {
+			content = content[1:]
+			continue
+		}
+		break
+	}
+	return content
+}
+
+// OpaqueUint64 defines an unsigned 64 bit value, that is wrapped into an Opaque (tag 0x9f7b).
+type OpaqueUint64 uint64
+
+// Type returns TypeOpaque.
+func (u OpaqueUint64) Type() Type { return TypeOpaque }
+
+// ByteSize returns the number of bytes, the value would need in the encoded version.
+func (u OpaqueUint64) ByteSize() int {
+	var data [9]byte
+	return wire.OctetsSize(3 + len(u.content(&data)))
+}
+
+// AppendBinary appends the encoded value to b and returns the extended slice.
+func (u OpaqueUint64) AppendBinary(b []byte) ([]byte, error) {
+	var data [9]byte
+	return appendOpaque(b, opaqueTagUint64, u.content(&data)), nil
+}
+
+// content returns the minimal encoding of the value, with a leading zero byte
+// if the highest bit is set.
+func (u OpaqueUint64) content(data *[9]byte) []byte {
+	binary.BigEndian.PutUint64(data[1:], uint64(u))
+	content := data[:]
+	for len(content) > 1 && content[0] == 0x00 && content[1]&0x80 == 0 {
+		content = content[1:]
+	}
+	return content
+}
+
+// ParseOpaque returns the value, that is wrapped into the provided opaque. It
+// returns an OpaqueFloat, OpaqueDouble, OpaqueInt64 or OpaqueUint64. Wrapped
+// Counter64 values (tag 0x9f76) are returned as OpaqueUint64.
+func ParseOpaque(o []byte) (Value, error) {
+	if len(o) < 3 || o[0] != opaqueTagExtension {
+		return nil, fmt.Errorf("%w: missing wrapped value", ErrInvalidOpaque)
+	}
+	tag, data := o[1], o[3:]
+	if int(o[2]) != len(data) {
+		return nil, fmt.Errorf("%w: length %d doesn't match %d content bytes", ErrInvalidOpaque, o[2], len(data))
+	}
+
+	switch tag {
+	case opaqueTagFloat:
+		if len(data) != 4 {
+			return nil, fmt.Errorf("%w: float with %d bytes", ErrInvalidOpaque, len(data))
+		}
+		return OpaqueFloat(math.Float32frombits(binary.BigEndian.Uint32(data))), nil
+	case opaqueTagDouble:
+		if len(data) != 8 {
+			return nil, fmt.Errorf("%w: double with %d bytes", ErrInvalidOpaque, len(data))
+		}
+		return OpaqueDouble(math.Float64frombits(binary.BigEndian.Uint64(data))), nil
+	case opaqueTagInt64:
+		if len(data) == 0 || len(data) > 8 {
+			return nil, fmt.Errorf("%w: int64 with %d bytes", ErrInvalidOpaque, len(data))
+		}
+		var value int64
+		if data[0]&0x80 != 0 {
+			value = -1
+		}
+		for _, c := range data {
+			value = value<<8 | int64(c)
+		}
+		return OpaqueInt64(value), nil
+	case opaqueTagUint64, opaqueTagCounter64:
+		if len(data) == 9 && data[0] == 0x00 {
+			data = data[1:]
+		}
+		if len(data) == 0 || len(data) > 8 {
+			return nil, fmt.Errorf("%w: uint64 with %d bytes", ErrInvalidOpaque, len(data))
+		}
+		var value uint64
+		for _, c := range data {
+			value = value<<8 | uint64(c)
+		}
+		return OpaqueUint64(value), nil
+	}
+	return nil, fmt.Errorf("%w: unsupported tag 0x%02x%02x", ErrInvalidOpaque, o[0], tag)
+}
+
+// appendOpaque appends the opaque, that wraps data with the provided tag, to b.
+func appendOpaque(b []byte, tag byte, data []byte) []byte {
+	l := 3 + len(data)
+	b = binary.LittleEndian.AppendUint32(b, uint32(l))
+	b = append(b, opaqueTagExtension, tag, byte(len(data)))
+	b = append(b, data...)
+	return wire.AppendPadding(b, l)
+}
@@ -0,0 +1,91 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package value_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Olian04/go-agentx/value"
+)
+
+func TestOpaqueEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    value.Value
+		expected []byte
+	}{
+		{"float", value.OpaqueFloat(1.5), []byte{7, 0, 0, 0, 0x9f, 0x78, 4, 0x3f, 0xc0, 0, 0, 0}},
+		{"double", value.OpaqueDouble(1.5), []byte{11, 0, 0, 0, 0x9f, 0x79, 8, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0, 0}},
+		{"int64 zero", value.OpaqueInt64(0), []byte{4, 0, 0, 0, 0x9f, 0x7a, 1, 0}},
+		{"int64 positive", value.OpaqueInt64(128), []byte{5, 0, 0, 0, 0x9f, 0x7a, 2, 0, 0x80, 0, 0, 0}},
+		{"int64 negative", value.OpaqueInt64(-129), []byte{5, 0, 0, 0, 0x9f, 0x7a, 2, 0xff, 0x7f, 0, 0, 0}},
+		{"int64 minus one", value.OpaqueInt64(-1), []byte{4, 0, 0, 0, 0x9f, 0x7a, 1, 0xff}},
+		{"uint64 small", value.OpaqueUint64(127), []byte{4, 0, 0, 0, 0x9f, 0x7b, 1, 0x7f}},
+		{"uint64 max", value.OpaqueUint64(math.MaxUint64),
+			[]byte{12, 0, 0, 0, 0x9f, 0x7b, 9, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, value.TypeOpaque, test.value.Type())
+			data, err := test.value.AppendBinary(nil)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, data)
+			assert.Equal(t, len(test.expected), test.value.ByteSize())
+
+			parsed, err := value.ParseOpaque(test.expected[4 : 4+test.expected[0]])
+			require.NoError(t, err)
+			assert.Equal(t, test.value, parsed)
+		})
+	}
+}
+
+func TestOpaqueRoundTrip(t *testing.T) {
+	for _, v := range []int64{math.MinInt64, math.MinInt32, -256, -128, 127, 255, 1 << 40, math.MaxInt64} {
+		data, err := value.OpaqueInt64(v).AppendBinary(nil)
+		require.NoError(t, err)
+		parsed, err := value.ParseOpaque(data[4 : 4+data[0]])
+		require.NoError(t, err)
+		assert.Equal(t, value.OpaqueInt64(v), parsed)
+	}
+	for _, v := range []uint64{0, 128, 1 << 63, math.MaxUint32} {
+		data, err := value.OpaqueUint64(v).AppendBinary(nil)
+		require.NoError(t, err)
+		parsed, err := value.ParseOpaque(data[4 : 4+data[0]])
+		require.NoError(t, err)
+		assert.Equal(t, value.OpaqueUint64(v), parsed)
+	}
+}
+
+func TestParseOpaqueCounter64(t *testing.T) {
+	parsed, err := value.ParseOpaque([]byte{0x9f, 0x76, 2, 0x01, 0x00})
+	require.NoError(t, err)
+	assert.Equal(t, value.OpaqueUint64(256), parsed)
+}
+
+func TestParseOpaqueInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"raw opaque", []byte{1, 2, 3, 4}},
+		{"length mismatch", []byte{0x9f, 0x78, 4, 0, 0}},
+		{"short float", []byte{0x9f, 0x78, 2, 0, 0}},
+		{"short double", []byte{0x9f, 0x79, 4, 0, 0, 0, 0}},
+		{"empty int64", []byte{0x9f, 0x7a, 0}},
+		{"long int64", []byte{0x9f, 0x7a, 9, 1, 0, 0, 0, 0, 0, 0, 0, 0}},
+		{"unsupported tag", []byte{0x9f, 0x70, 1, 0}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := value.ParseOpaque(test.data)
+			assert.ErrorIs(t, err, value.ErrInvalidOpaque)
+		})
+	}
+}
@@ -0,0 +1,304 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package value
+
+import (
+	"slices"
+	"sort"
+)
+
+// Tree defines an ordered map from oids to values, implemented as a trie with
+// one level per subidentifier. Insert, delete, lookup and successor search take
+// O(depth · log(fan-out)) time. Appending oids in ascending order is the fast path.
+// Clones share their nodes and copy only the path to a modified node (path
+// copying), so a clone costs O(1) and each following modification O(depth).
+// The zero value is an empty tree, ready to use. A tree is not safe for
+// concurrent modification.
+type Tree[V any] struct {
+	root *treeNode[V]
+	size int
+	// owner marks the nodes, that belong to this tree only and can be modified
+	// in place. All other nodes are shared with clones and are copied first.
+	owner *treeOwner
+}
+
+type treeOwner struct {
+	// the field ensures distinct addresses of the owners
+	_ byte
+}
+
+type treeNode[V any] struct {
+	subidentifier uint32
+	owner         *treeOwner
+	// key is the oid of the node and only set, if the node carries a value.
+	key      OID
+	value    V
+	children []*treeNode[V]
+}
+
+// Len returns the number of values in the tree.
+func (t *Tree[V]) Len() int {
+	return t.size
+}
+
+// Set sets the value for the provided oid. It returns true, if an existing
+// value was replaced.
+func (t *Tree[V]) Set(oid OID, v V) bool {
+	n := t.mutableRoot()
+	for _, subidentifier := range oid {
+		n = t.child(n, subidentifier)
+	}
+	replaced := n.key != nil
+	if !replaced {
+		n.key = oid.Clone()
+		if n.key == nil {
+			n.key = OID{}
+		}
+		t.size++
+	}
+	n.value = v
+	return replaced
+}
+
+// Get returns the value of the provided oid.
+func (t *Tree[V]) Get(oid OID) (V, bool) {
+	if n := t.lookup(oid); n != nil {
+		return n.value, n.key != nil
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete removes the value of the provided oid. It returns true, if a value
+// was removed.
+func (t *Tree[V]) Delete(oid OID) bool {
+	if n := t.lookup(oid); n == nil || n.key == nil {
+		return false
+	}
+	t.delete(t.mutableRoot(), oid)
+	t.size--
+	return true
+}
+
+// Successor returns the first oid (and its value) in the tree, that is greater
+// than the provided one or equal to it, if include is set.
+func (t *Tree[V]) Successor(oid OID, include bool) (OID, V, bool) {
+	if t.root != nil {
+		if n := t.root.successor(oid, include); n != nil {
+			return n.key, n.value, true
+		}
+	}
+	var zero V
+	return nil, zero, false
+}
+
+// Walk calls fn for all oids (and their values) in ascending order, starting
+// with the successor of the provided oid (see Successor). It stops, if fn returns
+// false. The tree must not be modified during the walk.
+func (t *Tree[V]) Walk(from OID, include bool, fn func(OID, V) bool) {
+	if t.root != nil {
+		t.root.walk(from, include, fn)
+	}
+}
+
+// Clone returns a copy of the tree in O(1). The trees share their nodes until
+// they are modified. The values themselves are copied by assignment. As the
+// nodes of t become shared, Clone counts as modification of t.
+func (t *Tree[V]) Clone() *Tree[V] {
+	// neither tree owns the shared nodes anymore
+	t.owner = nil
+	return &Tree[V]{root: t.root, size: t.size}
+}
+
+// lookup returns the node of the provided oid or nil, if it doesn't exist.
+func (t *Tree[V]) lookup(oid OID) *treeNode[V] {
+	n := t.root
+	for _, subidentifier := range oid {
+		if n == nil {
+			return nil
+		}
+		i, found := n.find(subidentifier)
+		if !found {
+			return nil
+		}
+		n = n.children[i]
+	}
+	return n
+}
+
+// mutable returns n, if it is owned by the tree, otherwise a copy of n owned
+// by the tree. The owner of the tree must be set.
+func (t *Tree[V]) mutable(n *treeNode[V]) *treeNode[V] {
+	if n.owner == t.owner {
+		return n
+	}
+	c := *n
+	c.owner = t.owner
+	c.children = slices.Clone(n.children)
+	return &c
+}
+
+// mutableRoot returns the root node for modification and creates it if needed.
+func (t *Tree[V]) mutableRoot() *treeNode[V] {
+	if t.owner == nil {
+		t.owner = &treeOwner{}
+	}
+	if t.root == nil {
+		t.root = &treeNode[V]{owner: t.owner}
+	}
+	t.root = t.mutable(t.root)
+	return t.root
+}
+
+// child returns the child node of n with the provided subidentifier for
+// modification. It is created, if it doesn't exist. n must be owned by the tree.
+func (t *Tree[V]) child(n *treeNode[V], subidentifier uint32) *treeNode[V] {
+	i, found := n.find(subidentifier)
+	if found {
+		c := n.children[i]
+		if c.owner != t.owner {
+			c = t.mutable(c)
+			n.children[i] = c
+		}
+		return c
+	}
+	c := &treeNode[V]{subidentifier: subidentifier, owner: t.owner}
+	if i == len(n.children) {
+		n.children = append(n.children, c)
+	} else {
+		n.children = slices.Insert(n.children, i, c)
+	}
+	return c
+}
+
+// delete removes the value of the node at the provided oid below n and prunes
+// all nodes, that are left without value and children. The node must exist and
+// n must be owned by the tree.
+func (t *Tree[V]) delete(n *treeNode[V], oid OID) {
+	if len(oid) == 0 {
+		var zero V
+		n.key, n.value = nil, zero
+		return
+	}
+
+	i, _ := n.find(oid[0])
+	c := t.mutable(n.children[i])
+	n.children[i] = c
+	t.delete(c, oid[1:])
+	if c.key == nil && len(c.children) == 0 {
+		n.children = slices.Delete(n.children, i, i+1)
+	}
+}
+
+// find returns the index of the child with the provided subidentifier and
+// whether it exists. If it doesn't, the index is the insert position.
+func (n *treeNode[V]) find(subidentifier uint32) (int, bool) {
+	count := len(n.children)
+	// fast path for ascending inserts and lookups of the last child
+	if count == 0 || n.children[count-1].subidentifier < subidentifier {
+		return count, false
+	}
+	if n.children[count-1].subidentifier == subidentifier {
+		return count - 1, true
+	}
+	i := n.search(subidentifier)
+	return i, n.children[i].subidentifier == subidentifier
+}
+
+// search returns the index of the first child with a subidentifier greater or
+// equal to the provided one.
+func (n *treeNode[V]) search(subidentifier uint32) int {
+	return sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].subidentifier >= subidentifier
+	})
+}
+
+// successor returns the first node with a value below n, whose oid relative
+// to n is greater than (or equal to) the provided one.
+func (n *treeNode[V]) successor(oid OID, include bool) *treeNode[V] {
+	if len(oid) == 0 {
+		if include && n.key != nil {
+			return n
+		}
+		for _, c := range n.children {
+			if result := c.first(); result != nil {
+				return result
+			}
+		}
+		return nil
+	}
+
+	i := n.search(oid[0])
+	if i < len(n.children) && n.children[i].subidentifier == oid[0] {
+		if result := n.children[i].successor(oid[1:], include); result != nil {
+			return result
+		}
+		i++
+	}
+	for _, c := range n.children[i:] {
+		if result := c.first(); result != nil {
+			return result
+		}
+	}
+	return nil
+}
+
+// first returns the first node with a value in the subtree of n. As empty nodes
+// are pruned, this is found in O(depth).
+func (n *treeNode[V]) first() *treeNode[V] {
+	if n.key != nil {
+		return n
+	}
+	for _, c := range n.children {
+		if result := c.first(); result != nil {
+			return result
+		}
+	}
+	return nil
+}
+
+// walk calls fn for all nodes with a value below n, whose oid relative to n is
+// greater than (or equal to) the provided one. It returns false, if fn stopped
+// the walk.
+func (n *treeNode[V]) walk(oid OID, include bool, fn func(OID, V) bool) bool {
+	if len(oid) == 0 {
+		if include && n.key != nil && !fn(n.key, n.value) {
+			return false
+		}
+		for _, c := range n.children {
+			if !c.walkAll(fn) {
+				return false
+			}
+		}
+		return true
+	}
+
+	i := n.search(oid[0])
+	if i < len(n.children) && n.children[i].subidentifier == oid[0] {
+		if !n.children[i].walk(oid[1:], include, fn) {
+			return false
+		}
+		i++
+	}
+	for _, c := range n.children[i:] {
+		if !c.walkAll(fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// walkAll calls fn for all nodes with a value in the subtree of n.
+func (n *treeNode[V]) walkAll(fn func(OID, V) bool) bool {
+	if n.key != nil && !fn(n.key, n.value) {
+		return false
+	}
+	for _, c := range n.children {
+		if !c.walkAll(fn) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,222 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package value_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Olian04/go-agentx/value"
+)
+
+func TestTree(t *testing.T) {
+	tree := value.Tree[string]{}
+	assert.False(t, tree.Set(value.OID{1, 3, 6, 1, 2}, "b"))
+	assert.False(t, tree.Set(value.OID{1, 3, 6, 1, 1}, "a"))
+	assert.False(t, tree.Set(value.OID{1, 3, 6, 1, 2, 1}, "c"))
+	assert.False(t, tree.Set(value.OID{1, 3, 6, 2}, "d"))
+	assert.True(t, tree.Set(value.OID{1, 3, 6, 1, 2}, "B"))
+	assert.Equal(t, 4, tree.Len())
+
+	v, ok := tree.Get(value.OID{1, 3, 6, 1, 2})
+	assert.True(t, ok)
+	assert.Equal(t, "B", v)
+	_, ok = tree.Get(value.OID{1, 3, 6, 1})
+	assert.False(t, ok)
+	_, ok = tree.Get(value.OID{1, 3, 6, 1, 3})
+	assert.False(t, ok)
+
+	oid, v, ok := tree.Successor(value.OID{1, 3, 6, 1}, false)
+	assert.True(t, ok)
+	assert.Equal(t, value.OID{1, 3, 6, 1, 1}, oid)
+	assert.Equal(t, "a", v)
+	oid, _, _ = tree.Successor(value.OID{1, 3, 6, 1, 2}, true)
+	assert.Equal(t, value.OID{1, 3, 6, 1, 2}, oid)
+	oid, _, _ = tree.Successor(value.OID{1, 3, 6, 1, 2}, false)
+	assert.Equal(t, value.OID{1, 3, 6, 1, 2, 1}, oid)
+	oid, _, _ = tree.Successor(value.OID{1, 3, 6, 1, 2, 1, 5}, false)
+	assert.Equal(t, value.OID{1, 3, 6, 2}, oid)
+	_, _, ok = tree.Successor(value.OID{1, 3, 6, 2}, false)
+	assert.False(t, ok)
+
+	var walked []string
+	tree.Walk(value.OID{1, 3, 6, 1, 1}, false, func(oid value.OID, v string) bool {
+		walked = append(walked, v)
+		return v != "c"
+	})
+	assert.Equal(t, []string{"B", "c"}, walked)
+
+	clone := tree.Clone()
+	assert.True(t, tree.Delete(value.OID{1, 3, 6, 1, 2, 1}))
+	assert.False(t, tree.Delete(value.OID{1, 3, 6, 1, 2, 1}))
+	assert.False(t, tree.Delete(value.OID{1, 3, 6}))
+	assert.Equal(t, 3, tree.Len())
+	oid, _, _ = tree.Successor(value.OID{1, 3, 6, 1, 2}, false)
+	assert.Equal(t, value.OID{1, 3, 6, 2}, oid)
+
+	assert.Equal(t, 4, clone.Len())
+	_, ok = clone.Get(value.OID{1, 3, 6, 1, 2, 1})
+	assert.True(t, ok)
+}
+
+func TestTreeClone(t *testing.T) {
+	tree := &value.Tree[string]{}
+	for i, oid := range benchmarkOIDs(3000) {
+		tree.Set(oid, fmt.Sprint(i))
+	}
+
+	clone := tree.Clone()
+	tree.Set(value.OID{1, 3, 6, 1, 4, 1, 45995, 3, 1, 5}, "changed")
+	tree.Set(value.OID{1, 3, 6, 1, 4, 1, 45995, 3, 1, 1000}, "added")
+	assert.True(t, tree.Delete(value.OID{1, 3, 6, 1, 4, 1, 45995, 3, 2, 7}))
+
+	second := clone.Clone()
+	clone.Set(value.OID{1, 3, 6, 1, 4, 1, 45995, 3, 1, 5}, "clone")
+	assert.True(t, clone.Delete(value.OID{1, 3, 6, 1, 4, 1, 45995, 3, 0, 0}))
+
+	get := func(tree *value.Tree[string], oid ...uint32) string {
+		v, _ := tree.Get(append(value.OID{1, 3, 6, 1, 4, 1, 45995, 3}, oid...))
+		return v
+	}
+	assert.Equal(t, "changed", get(tree, 1, 5))
+	assert.Equal(t, "added", get(tree, 1, 1000))
+	assert.Equal(t, "", get(tree, 2, 7))
+	assert.Equal(t, "0", get(tree, 0, 0))
+	assert.Equal(t, 3000, tree.Len())
+
+	assert.Equal(t, "clone", get(clone, 1, 5))
+	assert.Equal(t, "", get(clone, 1, 1000))
+	assert.Equal(t, "2007", get(clone, 2, 7))
+	assert.Equal(t, "", get(clone, 0, 0))
+	assert.Equal(t, 2999, clone.Len())
+
+	assert.Equal(t, "1005", get(second, 1, 5))
+	assert.Equal(t, "0", get(second, 0, 0))
+	assert.Equal(t, 3000, second.Len())
+}
+
+func TestTreeEmptyOID(t *testing.T) {
+	tree := value.Tree[int]{}
+	_, _, ok := tree.Successor(nil, true)
+	assert.False(t, ok)
+
+	tree.Set(nil, 1)
+	tree.Set(value.OID{1}, 2)
+	oid, v, ok := tree.Successor(nil, true)
+	require.True(t, ok)
+	assert.Equal(t, value.OID{}, oid)
+	assert.Equal(t, 1, v)
+	oid, _, _ = tree.Successor(nil, false)
+	assert.Equal(t, value.OID{1}, oid)
+}
+
+func TestTreeMatchesSortedList(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	randomOID := func() value.OID {
+		oid := make(value.OID, 1+random.Intn(4))
+		for i := range oid {
+			oid[i] = uint32(random.Intn(4))
+		}
+		return oid
+	}
+
+	tree := value.Tree[int]{}
+	var oids []value.OID
+	for i := 0; i < 2000; i++ {
+		oid := randomOID()
+		index := value.LowerBound(oids, oid, true)
+		exists := index < len(oids) && value.CompareOIDs(oids[index], oid) == 0
+		if random.Intn(3) == 0 {
+			assert.Equal(t, exists, tree.Delete(oid))
+			if exists {
+				oids = append(oids[:index], oids[index+1:]...)
+			}
+		} else {
+			assert.Equal(t, exists, tree.Set(oid, i))
+			if !exists {
+				oids = value.InsertSorted(oids, oid)
+			}
+		}
+		require.Equal(t, len(oids), tree.Len())
+
+		from, include := randomOID(), random.Intn(2) == 0
+		index = value.LowerBound(oids, from, include)
+		successor, _, ok := tree.Successor(from, include)
+		if index == len(oids) {
+			assert.False(t, ok)
+		} else {
+			assert.Equal(t, oids[index], successor)
+		}
+	}
+
+	var walked []value.OID
+	tree.Walk(nil, true, func(oid value.OID, _ int) bool {
+		walked = append(walked, oid)
+		return true
+	})
+	assert.Equal(t, oids, walked)
+}
+
+func benchmarkOIDs(count int) []value.OID {
+	oids := make([]value.OID, count)
+	for i := range oids {
+		oids[i] = value.OID{1, 3, 6, 1, 4, 1, 45995, 3, uint32(i / 1000), uint32(i % 1000)}
+	}
+	return oids
+}
+
+func BenchmarkTreeBulkLoad(b *testing.B) {
+	oids := benchmarkOIDs(100000)
+	b.ReportAllocs()
+	for b.Loop() {
+		tree := value.Tree[int]{}
+		for i, oid := range oids {
+			tree.Set(oid, i)
+		}
+	}
+}
+
+func BenchmarkTreeBulkLoadRandom(b *testing.B) {
+	oids := benchmarkOIDs(100000)
+	rand.New(rand.NewSource(1)).Shuffle(len(oids), func(i, j int) { oids[i], oids[j] = oids[j], oids[i] })
+	b.ReportAllocs()
+	for b.Loop() {
+		tree := value.Tree[int]{}
+		for i, oid := range oids {
+			tree.Set(oid, i)
+		}
+	}
+}
+
+func BenchmarkTreeSuccessorWalk(b *testing.B) {
+	tree := value.Tree[int]{}
+	for i, oid := range benchmarkOIDs(100000) {
+		tree.Set(oid, i)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		oid, _, ok := value.OID{1, 3, 6, 1, 4, 1, 45995}, 0, true
+		for ok {
+			oid, _, ok = tree.Successor(oid, false)
+		}
+	}
+}
+
+func BenchmarkTreeCloneSet(b *testing.B) {
+	tree := &value.Tree[int]{}
+	for i, oid := range benchmarkOIDs(1000000) {
+		tree.Set(oid, i)
+	}
+	oid := value.OID{1, 3, 6, 1, 4, 1, 45995, 3, 500, 500}
+	b.ReportAllocs()
+	for b.Loop() {
+		tree = tree.Clone()
+		tree.Set(oid, 0)
+	}
+}
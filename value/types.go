@@ -0,0 +1,206 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package value
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/Olian04/go-agentx/internal/wire"
+)
+
+// Integer32 defines an INTEGER / Integer32 value.
+type Integer32 int32
+
+// Type returns TypeInteger.
+func (i Integer32) Type() Type { return TypeInteger }
+
+// ByteSize returns the number of bytes, the value would need in the encoded version.
+func (i Integer32) ByteSize() int { return 4 }
+
+// AppendBinary appends the encoded value to b and returns the extended slice.
+func (i Integer32) AppendBinary(b []byte) ([]byte, error) {
+	return binary.LittleEndian.AppendUint32(b, uint32(i)), nil
+}
+
+// OctetString defines an OCTET STRING value. It can hold arbitrary binary data
+// of up to MaxOctetStringLength bytes.
+type OctetString []byte
+
+// Type returns TypeOctetString.
+func (o OctetString) Type() Type { return TypeOctetString }
+
+// ByteSize returns the number of bytes, the value would need in the encoded version.
+func (o OctetString) ByteSize() int { return wire.OctetsSize(len(o)) }
+
+// AppendBinary appends the encoded value to b and returns the extended slice.
+func (o OctetString) AppendBinary(b []byte) ([]byte, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	return wire.AppendOctets(b, o), nil
+}
+
+// Validate returns an error if the octet string exceeds MaxOctetStringLength.
+func (o OctetString) Validate() error {
+	return validateLength(TypeOctetString, len(o), MaxOctetStringLength)
+}
+
+func (o OctetString) String() string {
+	return string(o)
+}
+
+// DisplayString defines an OCTET STRING value, that holds text of up to
+// MaxDisplayStringLength bytes (RFC 2579).
+type DisplayString string
+
+// Type returns TypeOctetString.
+func (d DisplayString) Type() Type { return TypeOctetString }
+
+// ByteSize returns the number of bytes, the value would need in the encoded version.
+func (d DisplayString) ByteSize() int { return wire.OctetsSize(len(d)) }
+
+// AppendBinary appends the encoded value to b and returns the extended slice.
+func (d DisplayString) AppendBinary(b []byte) ([]byte, error) {
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	return wire.AppendOctets(b, []byte(d)), nil
+}
+
+// Validate returns an error if the display string exceeds MaxDisplayStringLength.
+func (d DisplayString) Validate() error {
+	return validateLength(TypeOctetString, len(d), MaxDisplayStringLength)
+}
+
+// Counter32 defines a Counter32 value.
+type Counter32 uint32
+
+// Type returns TypeCounter32.
+func (c Counter32) Type() Type { return TypeCounter32 }
+
+// ByteSize returns the number of bytes, the value would need in the encoded version.
+func (c Counter32) ByteSize() int { return 4 }
+
+// AppendBinary appends the encoded value to b and returns the extended slice.
+func (c Counter32) AppendBinary(b []byte) ([]byte, error) {
+	return binary.LittleEndian.AppendUint32(b, uint32(c)), nil
+}
+
+// Gauge32 defines a Gauge32 / Unsigned32 value.
+type Gauge32 uint32
+
+// Type returns TypeGauge32.
+func (g Gauge32) Type() Type { return TypeGauge32 }
+
+// ByteSize returns the number of bytes, the value would need in the encoded version.
+func (g Gauge32) ByteSize() int { return 4 }
+
+// AppendBinary appends the encoded value to b and returns the extended slice.
+func (g Gauge32) AppendBinary(b []byte) ([]byte, error) {
+	return binary.LittleEndian.AppendUint32(b, uint32(g)), nil
+}
+
+// TimeTicks defines a TimeTicks value in hundredths of a second.
+type TimeTicks uint32
+
+// NewTimeTicks returns the provided duration as TimeTicks.
+func NewTimeTicks(d time.Duration) TimeTicks {
+	return TimeTicks(d / (time.Second / 100))
+}
+
+// Duration returns the time ticks as a duration.
+func (t TimeTicks) Duration() time.Duration {
+	return time.Duration(t) * time.Second / 100
+}
+
+// Type returns TypeTimeTicks.
+func (t TimeTicks) Type() Type { return TypeTimeTicks }
+
+// ByteSize returns the number of bytes, the value would need in the encoded version.
+func (t TimeTicks) ByteSize() int { return 4 }
+
+// AppendBinary appends the encoded value to b and returns the extended slice.
+func (t TimeTicks) AppendBinary(b []byte) ([]byte, error) {
+	return binary.LittleEndian.AppendUint32(b, uint32(t)), nil
+}
+
+func (t TimeTicks) String() string {
+	return t.Duration().String()
+}
+
+// Counter64 defines a Counter64 value.
+type Counter64 uint64
+
+// Type returns TypeCounter64.
+func (c Counter64) Type() Type { return TypeCounter64 }
+
+// ByteSize returns the number of bytes, the value would need in the encoded version.
+func (c Counter64) ByteSize() int { return 8 }
+
+// AppendBinary appends the encoded value to b and returns the extended slice.
+func (c Counter64) AppendBinary(b []byte) ([]byte, error) {
+	return binary.LittleEndian.AppendUint64(b, uint64(c)), nil
+}
+
+// IPAddress defines an IpAddress value.
+type IPAddress [4]byte
+
+// Type returns TypeIPAddress.
+func (i IPAddress) Type() Type { return TypeIPAddress }
+
+// ByteSize returns the number of bytes, the value would need in the encoded version.
+func (i IPAddress) ByteSize() int { return wire.OctetsSize(len(i)) }
+
+// AppendBinary appends the encoded value to b and returns the extended slice.
+func (i IPAddress) AppendBinary(b []byte) ([]byte, error) {
+	return wire.AppendOctets(b, i[:]), nil
+}
+
+// Addr returns the address as netip.Addr.
+func (i IPAddress) Addr() netip.Addr {
+	return netip.AddrFrom4(i)
+}
+
+func (i IPAddress) String() string {
+	return net.IP(i[:]).String()
+}
+
+// Opaque defines an Opaque value.
+type Opaque []byte
+
+// Type returns TypeOpaque.
+func (o Opaque) Type() Type { return TypeOpaque }
+
+// ByteSize returns the number of bytes, the value would need in the encoded version.
+func (o Opaque) ByteSize() int { return wire.OctetsSize(len(o)) }
+
+// AppendBinary appends the encoded value to b and returns the extended slice.
+func (o Opaque) AppendBinary(b []byte) ([]byte, error) {
+	return wire.AppendOctets(b, o), nil
+}
+
+// Type returns TypeObjectIdentifier.
+func (o OID) Type() Type { return TypeObjectIdentifier }
+
+// ByteSize returns the number of bytes, the value would need in the encoded version.
+func (o OID) ByteSize() int {
+	_, subids := wire.CompressOID(o)
+	return wire.OIDSize(len(subids))
+}
+
+// AppendBinary appends the encoded value to b and returns the extended slice.
+func (o OID) AppendBinary(b []byte) ([]byte, error) {
+	prefix, subids := wire.CompressOID(o)
+	// the include field is always false for values
+	b, err := wire.AppendOID(b, prefix, 0x00, subids)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidOID, err)
+	}
+	return b, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package value_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Olian04/go-agentx/value"
+)
+
+func TestValueEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    value.Value
+		typ      value.Type
+		expected []byte
+	}{
+		{"Integer32", value.Integer32(-2), value.TypeInteger, []byte{0xfe, 0xff, 0xff, 0xff}},
+		{"OctetString", value.OctetString("abcde"), value.TypeOctetString, []byte{5, 0, 0, 0, 'a', 'b', 'c', 'd', 'e', 0, 0, 0}},
+		{"Counter32", value.Counter32(1), value.TypeCounter32, []byte{1, 0, 0, 0}},
+		{"Gauge32", value.Gauge32(2), value.TypeGauge32, []byte{2, 0, 0, 0}},
+		{"TimeTicks", value.NewTimeTicks(3 * time.Second), value.TypeTimeTicks, []byte{44, 1, 0, 0}},
+		{"Counter64", value.Counter64(1 << 32), value.TypeCounter64, []byte{0, 0, 0, 0, 1, 0, 0, 0}},
+		{"IPAddress", value.IPAddress{10, 0, 0, 1}, value.TypeIPAddress, []byte{4, 0, 0, 0, 10, 0, 0, 1}},
+		{"Opaque", value.Opaque{1}, value.TypeOpaque, []byte{1, 0, 0, 0, 1, 0, 0, 0}},
+		{"OID", value.OID{1, 3, 6, 1, 4, 1, 7}, value.TypeObjectIdentifier, []byte{2, 4, 0, 0, 1, 0, 0, 0, 7, 0, 0, 0}},
+		{"OID without prefix", value.OID{1, 2}, value.TypeObjectIdentifier, []byte{2, 0, 0, 0, 1, 0, 0, 0, 2, 0, 0, 0}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.typ, test.value.Type())
+			data, err := test.value.AppendBinary(nil)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, data)
+			assert.Equal(t, len(test.expected), test.value.ByteSize())
+		})
+	}
+}
+
+func TestTimeTicks(t *testing.T) {
+	ticks := value.NewTimeTicks(1500 * time.Millisecond)
+	assert.Equal(t, value.TimeTicks(150), ticks)
+	assert.Equal(t, 1500*time.Millisecond, ticks.Duration())
+}
+
+func TestOctetStringLimits(t *testing.T) {
+	_, err := value.OctetString(make([]byte, value.MaxOctetStringLength)).AppendBinary(nil)
+	assert.NoError(t, err)
+	_, err = value.OctetString(make([]byte, value.MaxOctetStringLength+1)).AppendBinary(nil)
+	assert.ErrorIs(t, err, value.ErrTooLong)
+
+	_, err = value.DisplayString(make([]byte, value.MaxDisplayStringLength)).AppendBinary(nil)
+	assert.NoError(t, err)
+	_, err = value.DisplayString(make([]byte, value.MaxDisplayStringLength+1)).AppendBinary(nil)
+	assert.ErrorIs(t, err, value.ErrTooLong)
+
+	_, err = value.Convert(value.TypeOctetString, value.DisplayString(make([]byte, value.MaxDisplayStringLength+1)))
+	assert.ErrorIs(t, err, value.ErrConversion)
+	_, err = value.Convert(value.TypeOctetString, make([]byte, value.MaxOctetStringLength+1))
+	assert.ErrorIs(t, err, value.ErrConversion)
+}
@@ -0,0 +1,91 @@
+// Copyright 2018 The agentx authors
+// Licensed under the LGPLv3 with static-linking exception.
+// See LICENCE file for details.
+
+package value
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The various value types. They match the variable types of the AgentX protocol.
+const (
+	TypeInteger          Type = 2
+	TypeOctetString      Type = 4
+	TypeNull             Type = 5
+	TypeObjectIdentifier Type = 6
+	TypeIPAddress        Type = 64
+	TypeCounter32        Type = 65
+	TypeGauge32          Type = 66
+	TypeTimeTicks        Type = 67
+	TypeOpaque           Type = 68
+	TypeCounter64        Type = 70
+)
+
+// The size limits of octet strings.
+const (
+	// MaxOctetStringLength defines the maximum length of an OCTET STRING (RFC 2578).
+	MaxOctetStringLength = 65535
+
+	// MaxDisplayStringLength defines the maximum length of a DisplayString (RFC 2579).
+	MaxDisplayStringLength = 255
+)
+
+// ErrTooLong is returned (wrapped), if a value exceeds the size limit of its type.
+var ErrTooLong = errors.New("value too long")
+
+// Type defines the type of a value.
+type Type uint16
+
+func (t Type) String() string {
+	switch t {
+	case TypeInteger:
+		return "Integer32"
+	case TypeOctetString:
+		return "OctetString"
+	case TypeNull:
+		return "Null"
+	case TypeObjectIdentifier:
+		return "ObjectIdentifier"
+	case TypeIPAddress:
+		return "IPAddress"
+	case TypeCounter32:
+		return "Counter32"
+	case TypeGauge32:
+		return "Gauge32"
+	case TypeTimeTicks:
+		return "TimeTicks"
+	case TypeOpaque:
+		return "Opaque"
+	case TypeCounter64:
+		return "Counter64"
+	}
+	return fmt.Sprintf("TypeUnknown (%d)", t)
+}
+
+// Value defines a typed value, that carries its own type and knows how it is
+// encoded in the AgentX protocol.
+type Value interface {
+	// Type returns the type of the value.
+	Type() Type
+
+	// ByteSize returns the number of bytes, the value would need in the encoded version.
+	ByteSize() int
+
+	// AppendBinary appends the encoded value to b and returns the extended slice.
+	AppendBinary(b []byte) ([]byte, error)
+}
+
+// validator defines a value, that can validate its limits.
+type validator interface {
+	Validate() error
+}
+
+// validateLength returns an error if length exceeds max.
+func validateLength(t Type, length, max int) error {
+	if length > max {
+		return fmt.Errorf("%w: %s of %d bytes exceeds %d bytes", ErrTooLong, t, length, max)
+	}
+	return nil
+}